@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/supabase/cli/internal/advisors"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/flags"
+)
+
+var (
+	advisorType = utils.EnumFlag{
+		Allowed: advisors.AllowedTypes,
+		Value:   advisors.TypeAll,
+	}
+	advisorLevel = utils.EnumFlag{
+		Allowed: advisors.AllowedLevels,
+		Value:   advisors.AllowedLevels[0],
+	}
+	advisorFailOn = utils.EnumFlag{
+		Allowed: append([]string{"none"}, advisors.AllowedLevels...),
+		Value:   "none",
+	}
+
+	advisorsCmd = &cobra.Command{
+		GroupID: groupManagementAPI,
+		Use:     "advisors",
+		Short:   "Show security and performance advisors of your project",
+		Long:    "Show security and performance findings reported by the advisors of the linked project.",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return advisors.Run(cmd.Context(), flags.ProjectRef, advisorType.Value, advisorLevel.Value, advisorFailOn.Value)
+		},
+	}
+)
+
+func init() {
+	advisorFlags := advisorsCmd.Flags()
+	advisorFlags.StringVar(&flags.ProjectRef, "project-ref", "", "Project ref of the Supabase project.")
+	advisorFlags.Var(&advisorType, "type", "Type of advisors to show.")
+	advisorFlags.Var(&advisorLevel, "level", "Minimum level of findings to show.")
+	advisorFlags.Var(&advisorFailOn, "fail-on", "Finding level to exit with non-zero status.")
+	rootCmd.AddCommand(advisorsCmd)
+}
@@ -4,6 +4,9 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/supabase/cli/internal/backups/list"
 	"github.com/supabase/cli/internal/backups/restore"
+	restorePointCreate "github.com/supabase/cli/internal/backups/restore_point/create"
+	restorePointList "github.com/supabase/cli/internal/backups/restore_point/list"
+	"github.com/supabase/cli/internal/backups/undo"
 	"github.com/supabase/cli/internal/utils/flags"
 )
 
@@ -33,6 +36,41 @@ var (
 			return restore.Run(cmd.Context(), timestamp)
 		},
 	}
+
+	restorePointCmd = &cobra.Command{
+		Use:   "restore-point",
+		Short: "Manage named restore points",
+	}
+
+	restorePointCreateCmd = &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a named restore point",
+		Long:  "Create a named restore point that can be used to undo subsequent changes, ie. before pushing risky migrations.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return restorePointCreate.Run(cmd.Context(), flags.ProjectRef, args[0])
+		},
+	}
+
+	restorePointName string
+
+	restorePointListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "Lists restore points",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return restorePointList.Run(cmd.Context(), flags.ProjectRef, restorePointName)
+		},
+	}
+
+	backupUndoCmd = &cobra.Command{
+		Use:   "undo <name>",
+		Short: "Undo changes made since a restore point",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return undo.Run(cmd.Context(), flags.ProjectRef, args[0])
+		},
+	}
 )
 
 func init() {
@@ -42,5 +80,10 @@ func init() {
 	restoreFlags := backupRestoreCmd.Flags()
 	restoreFlags.Int64VarP(&timestamp, "timestamp", "t", 0, "The recovery time target in seconds since epoch.")
 	backupsCmd.AddCommand(backupRestoreCmd)
+	restorePointCmd.AddCommand(restorePointCreateCmd)
+	restorePointListCmd.Flags().StringVar(&restorePointName, "name", "", "Only show the restore point with this name.")
+	restorePointCmd.AddCommand(restorePointListCmd)
+	backupsCmd.AddCommand(restorePointCmd)
+	backupsCmd.AddCommand(backupUndoCmd)
 	rootCmd.AddCommand(backupsCmd)
 }
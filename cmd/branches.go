@@ -10,10 +10,16 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/supabase/cli/internal/branches/create"
 	"github.com/supabase/cli/internal/branches/delete"
+	"github.com/supabase/cli/internal/branches/diff"
 	"github.com/supabase/cli/internal/branches/disable"
 	"github.com/supabase/cli/internal/branches/get"
 	"github.com/supabase/cli/internal/branches/list"
+	"github.com/supabase/cli/internal/branches/merge"
 	"github.com/supabase/cli/internal/branches/pause"
+	"github.com/supabase/cli/internal/branches/push"
+	"github.com/supabase/cli/internal/branches/reset"
+	"github.com/supabase/cli/internal/branches/restore"
+	"github.com/supabase/cli/internal/branches/runs"
 	"github.com/supabase/cli/internal/branches/unpause"
 	"github.com/supabase/cli/internal/branches/update"
 	"github.com/supabase/cli/internal/gen/keys"
@@ -188,6 +194,135 @@ var (
 		},
 	}
 
+	branchDiffCmd = &cobra.Command{
+		Use:   "diff [name]",
+		Short: "Show the schema diff of a preview branch",
+		Long:  "Show the schema changes of a preview branch compared to its parent project.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			fsys := afero.NewOsFs()
+			if len(args) > 0 {
+				branchId = args[0]
+			} else if err := promptBranchId(ctx, fsys); err != nil {
+				return err
+			}
+			return diff.Run(ctx, branchId, schema)
+		},
+	}
+
+	branchMergeCmd = &cobra.Command{
+		Use:   "merge [name]",
+		Short: "Merge a preview branch into its parent project",
+		Long:  "Merge the migrations of a preview branch into its parent project and wait for the merge to finish.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			fsys := afero.NewOsFs()
+			if len(args) > 0 {
+				branchId = args[0]
+			} else if err := promptBranchId(ctx, fsys); err != nil {
+				return err
+			}
+			return merge.Run(ctx, branchId, migrationVersion)
+		},
+	}
+
+	branchPushCmd = &cobra.Command{
+		Use:   "push [name]",
+		Short: "Push migrations to a preview branch",
+		Long:  "Apply the migrations of the associated git branch to a preview branch.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			fsys := afero.NewOsFs()
+			if len(args) > 0 {
+				branchId = args[0]
+			} else if err := promptBranchId(ctx, fsys); err != nil {
+				return err
+			}
+			return push.Run(ctx, branchId, migrationVersion)
+		},
+	}
+
+	branchResetCmd = &cobra.Command{
+		Use:   "reset [name]",
+		Short: "Reset a preview branch",
+		Long:  "Reset the database of a preview branch and reapply its migrations.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			fsys := afero.NewOsFs()
+			if len(args) > 0 {
+				branchId = args[0]
+			} else if err := promptBranchId(ctx, fsys); err != nil {
+				return err
+			}
+			return reset.Run(ctx, branchId, migrationVersion)
+		},
+	}
+
+	branchRestoreCmd = &cobra.Command{
+		Use:   "restore <name>",
+		Short: "Restore a deleted preview branch",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return restore.Run(cmd.Context(), args[0])
+		},
+	}
+
+	branchRunsCmd = &cobra.Command{
+		Use:   "runs",
+		Short: "Inspect action runs of a preview branch",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := cmd.Root().PersistentPreRunE(cmd, args); err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			fsys := afero.NewOsFs()
+			if len(branchId) == 0 {
+				if err := promptBranchId(ctx, fsys); err != nil {
+					return err
+				}
+			}
+			ref, err := pause.GetBranchProjectRef(ctx, branchId)
+			branchId = ref
+			return err
+		},
+	}
+
+	runsLimit uint
+
+	branchRunsListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List action runs of a preview branch",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runs.RunList(cmd.Context(), branchId, runsLimit)
+		},
+	}
+
+	branchRunsGetCmd = &cobra.Command{
+		Use:   "get <run-id>",
+		Short: "Show the steps of an action run",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runs.RunGet(cmd.Context(), branchId, args[0])
+		},
+	}
+
+	runsFollow bool
+
+	branchRunsLogsCmd = &cobra.Command{
+		Use:   "logs <run-id>",
+		Short: "Show the logs of an action run",
+		Long:  "Show the logs of an action run. With --follow, logs are streamed until the run finishes and the command exits with non-zero status if the run failed.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runs.RunLogs(cmd.Context(), branchId, args[0], runsFollow, os.Stdout)
+		},
+	}
+
 	branchDisableCmd = &cobra.Command{
 		Hidden: true,
 		Use:    "disable",
@@ -222,6 +357,23 @@ func init() {
 	branchesCmd.AddCommand(branchDisableCmd)
 	branchesCmd.AddCommand(branchPauseCmd)
 	branchesCmd.AddCommand(branchUnpauseCmd)
+	diffFlags := branchDiffCmd.Flags()
+	diffFlags.StringSliceVarP(&schema, "schema", "s", []string{}, "Comma separated list of schema to include.")
+	branchesCmd.AddCommand(branchDiffCmd)
+	branchMergeCmd.Flags().StringVar(&migrationVersion, "migration-version", "", "Merge migrations up to the specified version.")
+	branchesCmd.AddCommand(branchMergeCmd)
+	branchPushCmd.Flags().StringVar(&migrationVersion, "migration-version", "", "Push migrations up to the specified version.")
+	branchesCmd.AddCommand(branchPushCmd)
+	branchResetCmd.Flags().StringVar(&migrationVersion, "migration-version", "", "Reset to the specified migration version.")
+	branchesCmd.AddCommand(branchResetCmd)
+	branchesCmd.AddCommand(branchRestoreCmd)
+	branchRunsCmd.PersistentFlags().StringVar(&branchId, "branch", "", "Name or ID of the preview branch.")
+	branchRunsListCmd.Flags().UintVar(&runsLimit, "limit", 10, "Maximum number of runs to list.")
+	branchRunsCmd.AddCommand(branchRunsListCmd)
+	branchRunsCmd.AddCommand(branchRunsGetCmd)
+	branchRunsLogsCmd.Flags().BoolVarP(&runsFollow, "follow", "f", false, "Stream logs until the run finishes.")
+	branchRunsCmd.AddCommand(branchRunsLogsCmd)
+	branchesCmd.AddCommand(branchRunsCmd)
 	rootCmd.AddCommand(branchesCmd)
 }
 
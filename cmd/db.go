@@ -5,19 +5,24 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/go-errors/errors"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/supabase/cli/internal/db/branch/create"
 	"github.com/supabase/cli/internal/db/branch/delete"
 	"github.com/supabase/cli/internal/db/branch/list"
 	"github.com/supabase/cli/internal/db/branch/switch_"
 	"github.com/supabase/cli/internal/db/diff"
+	"github.com/supabase/cli/internal/db/drift"
 	"github.com/supabase/cli/internal/db/dump"
 	"github.com/supabase/cli/internal/db/lint"
 	"github.com/supabase/cli/internal/db/pull"
 	"github.com/supabase/cli/internal/db/push"
 	"github.com/supabase/cli/internal/db/reset"
+	"github.com/supabase/cli/internal/db/schema/apply"
+	"github.com/supabase/cli/internal/db/schema/plan"
 	"github.com/supabase/cli/internal/db/start"
 	"github.com/supabase/cli/internal/db/test"
 	"github.com/supabase/cli/internal/utils"
@@ -82,27 +87,78 @@ var (
 	usePgAdmin  bool
 	usePgSchema bool
 	usePgDelta  bool
+	diffEngine  string
+	compareWith string
 	schema      []string
 	file        string
+	viaApi      bool
 
 	dbDiffCmd = &cobra.Command{
 		Use:   "diff",
 		Short: "Diffs the local database for schema changes",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if usePgAdmin {
-				return diff.RunPgAdmin(cmd.Context(), schema, file, flags.DbConfig, afero.NewOsFs())
-			}
-			differ := diff.DiffSchemaMigra
 			if usePgSchema {
-				differ = diff.DiffPgSchema
 				fmt.Fprintln(os.Stderr, utils.Yellow("WARNING:"), "--use-pg-schema flag is experimental and may not include all entities, such as views and grants.")
-			} else if usePgDelta {
-				differ = diff.DiffPgDelta
+			}
+			differ, err := schemaDiffer(cmd.Flags())
+			if err != nil {
+				return err
 			}
 			return diff.Run(cmd.Context(), schema, file, flags.DbConfig, differ, afero.NewOsFs())
 		},
 	}
 
+	dbDriftCmd = &cobra.Command{
+		Use:   "drift",
+		Short: "Reports schema changes not tracked by local migrations",
+		Long:  "Applies local migrations to a shadow database and diffs it against the target database. Exits with non-zero status if any drift is found.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			differ, err := diff.GetEngine("")
+			if err != nil {
+				return err
+			}
+			return drift.Run(cmd.Context(), schema, flags.DbConfig, differ.Diff, afero.NewOsFs())
+		},
+	}
+
+	dbSchemaCmd = &cobra.Command{
+		Use:   "schema",
+		Short: "Manage database schema declaratively",
+		Long:  "Keep the database in sync with declarative schema files configured by schema_paths under [db.migrations] in config.toml.",
+	}
+
+	dbSchemaPlanCmd = &cobra.Command{
+		Use:   "plan",
+		Short: "Show changes required to match declared schema",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			differ, err := schemaDiffer(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			return plan.Run(cmd.Context(), schema, flags.DbConfig, differ, afero.NewOsFs())
+		},
+	}
+
+	dbSchemaApplyCmd = &cobra.Command{
+		Use:   "apply [migration name]",
+		Short: "Apply changes required to match declared schema",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := "declarative_schema"
+			if len(args) > 0 {
+				name = args[0]
+			}
+			differ, err := schemaDiffer(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			return apply.Run(cmd.Context(), schema, name, flags.DbConfig, differ, afero.NewOsFs())
+		},
+		PostRun: func(cmd *cobra.Command, args []string) {
+			fmt.Println("Finished " + utils.Aqua("supabase db schema apply") + ".")
+		},
+	}
+
 	dataOnly     bool
 	useCopy      bool
 	roleOnly     bool
@@ -139,14 +195,30 @@ var (
 
 	dryRun       bool
 	includeAll   bool
+	strictPush   bool
+	lockTimeout  string
+	stmtTimeout  string
 	includeRoles bool
 	includeSeed  bool
+	restorePoint string
 
 	dbPushCmd = &cobra.Command{
 		Use:   "push",
 		Short: "Push new migrations to the remote database",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return push.Run(cmd.Context(), dryRun, includeAll, includeRoles, includeSeed, flags.DbConfig, afero.NewOsFs())
+			if local, _ := cmd.Flags().GetBool("local"); len(restorePoint) > 0 && (local || cmd.Flags().Changed("db-url")) {
+				return errors.New("--restore-point can only be used with the linked project")
+			}
+			if viaApi {
+				return push.RunViaApi(cmd.Context(), dryRun, includeAll, restorePoint, flags.ProjectRef, afero.NewOsFs())
+			}
+			if cmd.Flags().Changed("lock-timeout") {
+				utils.Config.Db.Migrations.LockTimeout = lockTimeout
+			}
+			if cmd.Flags().Changed("statement-timeout") {
+				utils.Config.Db.Migrations.StatementTimeout = stmtTimeout
+			}
+			return push.Run(cmd.Context(), dryRun, includeAll, strictPush, includeRoles, includeSeed, restorePoint, flags.DbConfig, afero.NewOsFs())
 		},
 	}
 
@@ -177,7 +249,11 @@ var (
 		Short:      "Show changes on the remote database",
 		Long:       "Show changes on the remote database since last migration.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return diff.Run(cmd.Context(), schema, file, flags.DbConfig, diff.DiffSchemaMigra, afero.NewOsFs())
+			differ, err := diff.GetEngine("")
+			if err != nil {
+				return err
+			}
+			return diff.Run(cmd.Context(), schema, file, flags.DbConfig, differ.Diff, afero.NewOsFs())
 		},
 	}
 
@@ -213,12 +289,13 @@ var (
 		Allowed: append([]string{"none"}, lint.AllowedLevels...),
 		Value:   "none",
 	}
+	lintAdvisors bool
 
 	dbLintCmd = &cobra.Command{
 		Use:   "lint",
 		Short: "Checks local database for typing error",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return lint.Run(cmd.Context(), schema, level.Value, lintFailOn.Value, flags.DbConfig, afero.NewOsFs())
+			return lint.Run(cmd.Context(), schema, level.Value, lintFailOn.Value, lintAdvisors, flags.DbConfig, afero.NewOsFs())
 		},
 	}
 
@@ -255,7 +332,9 @@ func init() {
 	diffFlags.BoolVar(&usePgAdmin, "use-pgadmin", false, "Use pgAdmin to generate schema diff.")
 	diffFlags.BoolVar(&usePgSchema, "use-pg-schema", false, "Use pg-schema-diff to generate schema diff.")
 	diffFlags.BoolVar(&usePgDelta, "use-pg-delta", false, "Use pg-delta to generate schema diff.")
-	dbDiffCmd.MarkFlagsMutuallyExclusive("use-migra", "use-pgadmin", "use-pg-schema", "use-pg-delta")
+	diffFlags.StringVar(&diffEngine, "engine", "", "Name of the engine used to generate schema diff, or an external differ as exec:<command> or docker:<image>.")
+	diffFlags.StringVar(&compareWith, "compare", "", "Runs another engine on the same databases and reports statements that differ.")
+	dbDiffCmd.MarkFlagsMutuallyExclusive("use-migra", "use-pgadmin", "use-pg-schema", "use-pg-delta", "engine")
 	diffFlags.String("db-url", "", "Diffs against the database specified by the connection string (must be percent-encoded).")
 	diffFlags.Bool("linked", false, "Diffs local migration files against the linked project.")
 	diffFlags.Bool("local", true, "Diffs local migration files against the local database.")
@@ -263,6 +342,15 @@ func init() {
 	diffFlags.StringVarP(&file, "file", "f", "", "Saves schema diff to a new migration file.")
 	diffFlags.StringSliceVarP(&schema, "schema", "s", []string{}, "Comma separated list of schema to include.")
 	dbCmd.AddCommand(dbDiffCmd)
+	// Build drift command
+	driftFlags := dbDriftCmd.Flags()
+	driftFlags.String("db-url", "", "Checks drift on the database specified by the connection string (must be percent-encoded).")
+	driftFlags.Bool("linked", true, "Checks drift on the linked project.")
+	dbDriftCmd.MarkFlagsMutuallyExclusive("db-url", "linked")
+	driftFlags.StringVarP(&dbPassword, "password", "p", "", "Password to your remote Postgres database.")
+	cobra.CheckErr(viper.BindPFlag("DB_PASSWORD", driftFlags.Lookup("password")))
+	driftFlags.StringSliceVarP(&schema, "schema", "s", []string{}, "Comma separated list of schema to include.")
+	dbCmd.AddCommand(dbDriftCmd)
 	// Build dump command
 	dumpFlags := dbDumpCmd.Flags()
 	dumpFlags.BoolVar(&dryRun, "dry-run", false, "Prints the pg_dump script that would be executed.")
@@ -288,14 +376,44 @@ func init() {
 	pushFlags.BoolVar(&includeAll, "include-all", false, "Include all migrations not found on remote history table.")
 	pushFlags.BoolVar(&includeRoles, "include-roles", false, "Include custom roles from "+utils.CustomRolesPath+".")
 	pushFlags.BoolVar(&includeSeed, "include-seed", false, "Include seed data from your config.")
+	pushFlags.BoolVar(&strictPush, "strict", false, "Refuse to push when local migration files have drifted from the remote history table.")
 	pushFlags.BoolVar(&dryRun, "dry-run", false, "Print the migrations that would be applied, but don't actually apply them.")
+	pushFlags.StringVar(&lockTimeout, "lock-timeout", "", "Maximum time to wait for a lock when applying each migration, eg. 5s.")
+	pushFlags.StringVar(&stmtTimeout, "statement-timeout", "", "Maximum time each migration statement may run, eg. 10min.")
+	pushFlags.StringVar(&restorePoint, "restore-point", "", "Create a named restore point before pushing changes to the linked project.")
 	pushFlags.String("db-url", "", "Pushes to the database specified by the connection string (must be percent-encoded).")
 	pushFlags.Bool("linked", true, "Pushes to the linked project.")
 	pushFlags.Bool("local", false, "Pushes to the local database.")
 	dbPushCmd.MarkFlagsMutuallyExclusive("db-url", "linked", "local")
 	pushFlags.StringVarP(&dbPassword, "password", "p", "", "Password to your remote Postgres database.")
 	cobra.CheckErr(viper.BindPFlag("DB_PASSWORD", pushFlags.Lookup("password")))
+	pushFlags.BoolVar(&viaApi, "via-api", false, "Pushes to the linked project through the Management API.")
+	dbPushCmd.MarkFlagsMutuallyExclusive("via-api", "db-url", "local")
+	dbPushCmd.MarkFlagsMutuallyExclusive("via-api", "password")
+	dbPushCmd.MarkFlagsMutuallyExclusive("via-api", "include-roles")
+	dbPushCmd.MarkFlagsMutuallyExclusive("via-api", "include-seed")
+	dbPushCmd.MarkFlagsMutuallyExclusive("via-api", "strict")
+	dbPushCmd.MarkFlagsMutuallyExclusive("via-api", "lock-timeout")
+	dbPushCmd.MarkFlagsMutuallyExclusive("via-api", "statement-timeout")
 	dbCmd.AddCommand(dbPushCmd)
+	// Build schema command
+	for _, c := range []*cobra.Command{dbSchemaPlanCmd, dbSchemaApplyCmd} {
+		schemaFlags := c.Flags()
+		schemaFlags.BoolVar(&usePgSchema, "use-pg-schema", false, "Use pg-schema-diff to generate schema diff.")
+		schemaFlags.BoolVar(&usePgDelta, "use-pg-delta", false, "Use pg-delta to generate schema diff.")
+		schemaFlags.StringVar(&diffEngine, "engine", "", "Name of the engine used to generate schema diff, or an external differ as exec:<command> or docker:<image>.")
+		c.MarkFlagsMutuallyExclusive("use-pg-schema", "use-pg-delta", "engine")
+		schemaFlags.StringSliceVarP(&schema, "schema", "s", []string{}, "Comma separated list of schema to include.")
+		schemaFlags.String("db-url", "", "Targets the database specified by the connection string (must be percent-encoded).")
+		schemaFlags.Bool("linked", true, "Targets the linked project.")
+		schemaFlags.Bool("local", false, "Targets the local database.")
+		c.MarkFlagsMutuallyExclusive("db-url", "linked", "local")
+		schemaFlags.StringVarP(&dbPassword, "password", "p", "", "Password to your remote Postgres database.")
+		cobra.CheckErr(viper.BindPFlag("DB_PASSWORD", schemaFlags.Lookup("password")))
+		c.MarkFlagsMutuallyExclusive("db-url", "password")
+		dbSchemaCmd.AddCommand(c)
+	}
+	dbCmd.AddCommand(dbSchemaCmd)
 	// Build pull command
 	pullFlags := dbPullCmd.Flags()
 	pullFlags.StringSliceVarP(&schema, "schema", "s", []string{}, "Comma separated list of schema to include.")
@@ -337,6 +455,7 @@ func init() {
 	lintFlags.StringSliceVarP(&schema, "schema", "s", []string{}, "Comma separated list of schema to include.")
 	lintFlags.Var(&level, "level", "Error level to emit.")
 	lintFlags.Var(&lintFailOn, "fail-on", "Error level to exit with non-zero status.")
+	lintFlags.BoolVar(&lintAdvisors, "advisors", false, "Also run the security and performance rules of the hosted database advisors.")
 	dbCmd.AddCommand(dbLintCmd)
 	// Build start command
 	startFlags := dbStartCmd.Flags()
@@ -351,3 +470,26 @@ func init() {
 	dbTestCmd.MarkFlagsMutuallyExclusive("db-url", "linked", "local")
 	rootCmd.AddCommand(dbCmd)
 }
+
+// schemaDiffer resolves the engine selected by flags, falling back to diff_engine in config.toml.
+func schemaDiffer(flagSet *pflag.FlagSet) (diff.DiffFunc, error) {
+	name := diffEngine
+	if usePgAdmin {
+		name = diff.EnginePgAdmin
+	} else if usePgSchema {
+		name = diff.EnginePgSchema
+	} else if usePgDelta {
+		name = diff.EnginePgDelta
+	} else if useMigra && flagSet.Changed("use-migra") {
+		// Default value of --use-migra should not override config
+		name = diff.EngineMigra
+	}
+	if len(compareWith) > 0 {
+		return diff.CompareDiffer(name, compareWith, os.Stderr)
+	}
+	differ, err := diff.GetEngine(name)
+	if err != nil {
+		return nil, err
+	}
+	return differ.Diff, nil
+}
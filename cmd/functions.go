@@ -3,12 +3,12 @@ package cmd
 import (
 	"fmt"
 
-	"github.com/go-errors/errors"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/supabase/cli/internal/functions/delete"
 	"github.com/supabase/cli/internal/functions/deploy"
 	"github.com/supabase/cli/internal/functions/download"
+	"github.com/supabase/cli/internal/functions/invoke"
 	"github.com/supabase/cli/internal/functions/list"
 	new_ "github.com/supabase/cli/internal/functions/new"
 	"github.com/supabase/cli/internal/functions/serve"
@@ -66,6 +66,7 @@ var (
 	noVerifyJWT     = new(bool)
 	importMapPath   string
 	prune           bool
+	cacheDir        string
 
 	functionsDeployCmd = &cobra.Command{
 		Use:   "deploy [Function name]",
@@ -78,10 +79,8 @@ var (
 			}
 			if useApi {
 				useDocker = false
-			} else if maxJobs > 1 {
-				return errors.New("--jobs must be used together with --use-api")
 			}
-			return deploy.Run(cmd.Context(), args, useDocker, noVerifyJWT, importMapPath, maxJobs, prune, afero.NewOsFs())
+			return deploy.Run(cmd.Context(), args, useDocker, noVerifyJWT, importMapPath, maxJobs, cacheDir, prune, afero.NewOsFs())
 		},
 	}
 
@@ -134,6 +133,30 @@ var (
 			return serve.Run(cmd.Context(), envFilePath, noVerifyJWT, importMapPath, runtimeOption, afero.NewOsFs())
 		},
 	}
+
+	invokeRequest invoke.Request
+	invokeAuth    invoke.Auth
+	invokeLinked  bool
+
+	functionsInvokeCmd = &cobra.Command{
+		Use:   "invoke <Function name>",
+		Short: "Invoke a Function locally or on Supabase",
+		Long:  "Send a request to a Function served locally, or deployed to the linked Supabase project with --linked.",
+		Args:  cobra.ExactArgs(1),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if !invokeLinked && !cmd.Flags().Changed("project-ref") {
+				cmd.GroupID = groupLocalDev
+			}
+			return cmd.Root().PersistentPreRunE(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectRef := flags.ProjectRef
+			if cmd.GroupID == groupLocalDev {
+				projectRef = ""
+			}
+			return invoke.Run(cmd.Context(), args[0], invokeRequest, invokeAuth, projectRef, afero.NewOsFs())
+		},
+	}
 )
 
 func init() {
@@ -147,6 +170,7 @@ func init() {
 	cobra.CheckErr(deployFlags.MarkHidden("legacy-bundle"))
 	cobra.CheckErr(deployFlags.MarkHidden("use-docker"))
 	deployFlags.UintVarP(&maxJobs, "jobs", "j", 1, "Maximum number of parallel jobs.")
+	deployFlags.StringVar(&cacheDir, "cache-dir", "", "Directory to cache bundled functions between deploys. Remote imports must be pinned to a version.")
 	deployFlags.BoolVar(noVerifyJWT, "no-verify-jwt", false, "Disable JWT verification for the Function.")
 	deployFlags.BoolVar(&prune, "prune", false, "Delete Functions that exist in Supabase project but not locally.")
 	deployFlags.StringVar(&flags.ProjectRef, "project-ref", "", "Project ref of the Supabase project.")
@@ -168,11 +192,21 @@ func init() {
 	functionsDownloadCmd.MarkFlagsMutuallyExclusive("use-api", "use-docker", "legacy-bundle")
 	cobra.CheckErr(downloadFlags.MarkHidden("legacy-bundle"))
 	cobra.CheckErr(downloadFlags.MarkHidden("use-docker"))
+	invokeFlags := functionsInvokeCmd.Flags()
+	invokeFlags.StringVarP(&invokeRequest.Method, "method", "X", "", "HTTP method of the request. Defaults to POST if data is set, otherwise GET.")
+	invokeFlags.StringVarP(&invokeRequest.Data, "data", "d", "", "Request body. Use @<path> to read from a file, or @- to read from stdin.")
+	invokeFlags.StringArrayVarP(&invokeRequest.Headers, "header", "H", nil, "Request header in the format 'Key: Value'.")
+	invokeFlags.StringArrayVar(&invokeRequest.Query, "query", nil, "Query parameter in the format key=value.")
+	invokeFlags.StringVar(&invokeAuth.Role, "role", "", "Role of the bearer token. Defaults to the anon key if --role and --sub are not set.")
+	invokeFlags.StringVar(&invokeAuth.Subject, "sub", "", "User ID of the bearer token.")
+	invokeFlags.BoolVar(&invokeLinked, "linked", false, "Invokes the Function deployed to the linked project.")
+	invokeFlags.StringVar(&flags.ProjectRef, "project-ref", "", "Project ref of the Supabase project.")
 	functionsCmd.AddCommand(functionsListCmd)
 	functionsCmd.AddCommand(functionsDeleteCmd)
 	functionsCmd.AddCommand(functionsDeployCmd)
 	functionsCmd.AddCommand(functionsNewCmd)
 	functionsCmd.AddCommand(functionsServeCmd)
 	functionsCmd.AddCommand(functionsDownloadCmd)
+	functionsCmd.AddCommand(functionsInvokeCmd)
 	rootCmd.AddCommand(functionsCmd)
 }
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/supabase/cli/internal/jit/list"
+	"github.com/supabase/cli/internal/jit/request"
+	"github.com/supabase/cli/internal/jit/revoke"
+	"github.com/supabase/cli/internal/utils/flags"
+)
+
+var (
+	jitCmd = &cobra.Command{
+		GroupID: groupManagementAPI,
+		Use:     "jit",
+		Short:   "Manage just-in-time database access",
+		Long: `Just-in-time access grants your account a Postgres role for a limited time.
+
+Set the SUPABASE_DB_JIT_ROLE and SUPABASE_DB_JIT_RHOST environment variables to connect to the linked project with a granted role instead of the database password.`,
+	}
+
+	jitRole string
+
+	jitRequestCmd = &cobra.Command{
+		Use:   "request",
+		Short: "Request time-boxed access to a database role",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return request.Run(cmd.Context(), flags.ProjectRef, jitRole, viper.GetString("DB_JIT_RHOST"))
+		},
+	}
+
+	jitListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List active just-in-time access grants",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return list.Run(cmd.Context(), flags.ProjectRef)
+		},
+	}
+
+	jitUserId string
+
+	jitRevokeCmd = &cobra.Command{
+		Use:   "revoke",
+		Short: "Revoke just-in-time access grants of a user",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return revoke.Run(cmd.Context(), flags.ProjectRef, jitUserId)
+		},
+	}
+)
+
+func init() {
+	jitCmd.PersistentFlags().StringVar(&flags.ProjectRef, "project-ref", "", "Project ref of the Supabase project.")
+	requestFlags := jitRequestCmd.Flags()
+	requestFlags.StringVar(&jitRole, "role", "", "Postgres role to request access to.")
+	requestFlags.String("rhost", "", "Public IP address to connect from.")
+	cobra.CheckErr(viper.BindPFlag("DB_JIT_RHOST", requestFlags.Lookup("rhost")))
+	cobra.CheckErr(jitRequestCmd.MarkFlagRequired("role"))
+	jitCmd.AddCommand(jitRequestCmd)
+	jitCmd.AddCommand(jitListCmd)
+	jitRevokeCmd.Flags().StringVar(&jitUserId, "user-id", "", "ID of the user to revoke. Defaults to the current user.")
+	jitCmd.AddCommand(jitRevokeCmd)
+	rootCmd.AddCommand(jitCmd)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/supabase/cli/internal/logs"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/flags"
+)
+
+var (
+	logSource = utils.EnumFlag{
+		Allowed: logs.Sources,
+		Value:   logs.SourcePostgres,
+	}
+	logParams logs.Params
+	logSince  string
+	logUntil  string
+
+	logsCmd = &cobra.Command{
+		GroupID: groupManagementAPI,
+		Use:     "logs",
+		Short:   "Query and tail logs of your project",
+		Long: `Query logs of the linked project from a specific source.
+
+Use --sql to run a custom query against the logs explorer, or --follow to poll for new entries. A custom query must select the id and timestamp columns to be followed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if logParams.Start, err = logs.ParseTime(logSince); err != nil {
+				return err
+			}
+			if logParams.End, err = logs.ParseTime(logUntil); err != nil {
+				return err
+			}
+			logParams.Source = logSource.Value
+			return logs.Run(cmd.Context(), flags.ProjectRef, logParams, os.Stdout)
+		},
+	}
+)
+
+func init() {
+	logFlags := logsCmd.Flags()
+	logFlags.StringVar(&flags.ProjectRef, "project-ref", "", "Project ref of the Supabase project.")
+	logFlags.Var(&logSource, "source", "Source of the logs to query.")
+	logFlags.StringVar(&logParams.Filter, "filter", "", "SQL condition to filter log entries, ie. \"event_message like '%error%'\".")
+	logFlags.StringVar(&logParams.Sql, "sql", "", "Custom SQL query to run against the logs explorer.")
+	logFlags.UintVar(&logParams.Limit, "limit", 100, "Maximum number of entries to return.")
+	logFlags.StringVar(&logSince, "since", "1h", "Show logs since a RFC3339 timestamp or relative duration, ie. 30m.")
+	logFlags.StringVar(&logUntil, "until", "", "Show logs until a RFC3339 timestamp or relative duration.")
+	logFlags.BoolVarP(&logParams.Follow, "follow", "f", false, "Poll for new log entries until interrupted or the --until time is reached.")
+	logsCmd.MarkFlagsMutuallyExclusive("sql", "filter")
+	logsCmd.MarkFlagsMutuallyExclusive("sql", "source")
+	rootCmd.AddCommand(logsCmd)
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/supabase/cli/internal/migration/check"
+	"github.com/supabase/cli/internal/migration/data"
 	"github.com/supabase/cli/internal/migration/down"
 	"github.com/supabase/cli/internal/migration/fetch"
 	"github.com/supabase/cli/internal/migration/list"
@@ -30,6 +32,9 @@ var (
 		Use:   "list",
 		Short: "List local and remote migrations",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if viaApi {
+				return list.RunViaApi(cmd.Context(), flags.ProjectRef, afero.NewOsFs())
+			}
 			return list.Run(cmd.Context(), flags.DbConfig, afero.NewOsFs())
 		},
 	}
@@ -54,6 +59,9 @@ var (
 		Use:   "repair [version] ...",
 		Short: "Repair the migration history table",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if viaApi {
+				return repair.RunViaApi(cmd.Context(), flags.ProjectRef, args, targetStatus.Value, afero.NewOsFs())
+			}
 			return repair.Run(cmd.Context(), flags.DbConfig, args, targetStatus.Value, afero.NewOsFs())
 		},
 		PostRun: func(cmd *cobra.Command, args []string) {
@@ -96,6 +104,45 @@ var (
 		},
 	}
 
+	largeTableRows int64
+
+	checkFailOn = utils.EnumFlag{
+		Allowed: append([]string{"none"}, check.AllowedLevels...),
+		Value:   "none",
+	}
+
+	migrationCheckCmd = &cobra.Command{
+		Use:   "check",
+		Short: "Check pending migrations for unsafe statements",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return check.Run(cmd.Context(), largeTableRows, checkFailOn.Value, flags.DbConfig, afero.NewOsFs())
+		},
+	}
+
+	migrationDataCmd = &cobra.Command{
+		Use:   "data",
+		Short: "Manage batched data migration scripts",
+	}
+
+	migrationDataNewCmd = &cobra.Command{
+		Use:   "new <migration name>",
+		Short: "Create a data migration script from template",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return data.New(args[0], afero.NewOsFs())
+		},
+	}
+
+	migrationDataUpCmd = &cobra.Command{
+		Use:   "up",
+		Short: "Apply data migrations in batches",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return data.Run(cmd.Context(), flags.DbConfig, afero.NewOsFs())
+		},
+	}
+
 	migrationFetchCmd = &cobra.Command{
 		Use:   "fetch",
 		Short: "Fetch migration files from history table",
@@ -115,6 +162,9 @@ func init() {
 	listFlags.StringVarP(&dbPassword, "password", "p", "", "Password to your remote Postgres database.")
 	cobra.CheckErr(viper.BindPFlag("DB_PASSWORD", listFlags.Lookup("password")))
 	migrationListCmd.MarkFlagsMutuallyExclusive("db-url", "password")
+	listFlags.BoolVar(&viaApi, "via-api", false, "Lists migrations of the linked project through the Management API.")
+	migrationListCmd.MarkFlagsMutuallyExclusive("via-api", "db-url", "local")
+	migrationListCmd.MarkFlagsMutuallyExclusive("via-api", "password")
 	migrationCmd.AddCommand(migrationListCmd)
 	// Build repair command
 	repairFlags := migrationRepairCmd.Flags()
@@ -127,6 +177,9 @@ func init() {
 	repairFlags.StringVarP(&dbPassword, "password", "p", "", "Password to your remote Postgres database.")
 	cobra.CheckErr(viper.BindPFlag("DB_PASSWORD", repairFlags.Lookup("password")))
 	migrationRepairCmd.MarkFlagsMutuallyExclusive("db-url", "password")
+	repairFlags.BoolVar(&viaApi, "via-api", false, "Repairs the migration history of the linked project through the Management API.")
+	migrationRepairCmd.MarkFlagsMutuallyExclusive("via-api", "db-url", "local")
+	migrationRepairCmd.MarkFlagsMutuallyExclusive("via-api", "password")
 	migrationCmd.AddCommand(migrationRepairCmd)
 	// Build squash command
 	squashFlags := migrationSquashCmd.Flags()
@@ -154,6 +207,30 @@ func init() {
 	downFlags.Bool("local", true, "Resets applied migrations on the local database.")
 	migrationDownCmd.MarkFlagsMutuallyExclusive("db-url", "linked", "local")
 	migrationCmd.AddCommand(migrationDownCmd)
+	// Build check command
+	checkFlags := migrationCheckCmd.Flags()
+	checkFlags.Int64Var(&largeTableRows, "large-table-rows", 100000, "Estimated row count above which locking statements are reported as errors.")
+	checkFlags.Var(&checkFailOn, "fail-on", "Error level to exit with non-zero status.")
+	checkFlags.String("db-url", "", "Checks pending migrations against the database specified by the connection string (must be percent-encoded).")
+	checkFlags.Bool("linked", true, "Checks pending migrations against the linked project.")
+	checkFlags.Bool("local", false, "Checks pending migrations against the local database.")
+	migrationCheckCmd.MarkFlagsMutuallyExclusive("db-url", "linked", "local")
+	checkFlags.StringVarP(&dbPassword, "password", "p", "", "Password to your remote Postgres database.")
+	cobra.CheckErr(viper.BindPFlag("DB_PASSWORD", checkFlags.Lookup("password")))
+	migrationCheckCmd.MarkFlagsMutuallyExclusive("db-url", "password")
+	migrationCmd.AddCommand(migrationCheckCmd)
+	// Build data command
+	dataFlags := migrationDataUpCmd.Flags()
+	dataFlags.String("db-url", "", "Applies data migrations to the database specified by the connection string (must be percent-encoded).")
+	dataFlags.Bool("linked", false, "Applies data migrations to the linked project.")
+	dataFlags.Bool("local", true, "Applies data migrations to the local database.")
+	migrationDataUpCmd.MarkFlagsMutuallyExclusive("db-url", "linked", "local")
+	dataFlags.StringVarP(&dbPassword, "password", "p", "", "Password to your remote Postgres database.")
+	cobra.CheckErr(viper.BindPFlag("DB_PASSWORD", dataFlags.Lookup("password")))
+	migrationDataUpCmd.MarkFlagsMutuallyExclusive("db-url", "password")
+	migrationDataCmd.AddCommand(migrationDataNewCmd)
+	migrationDataCmd.AddCommand(migrationDataUpCmd)
+	migrationCmd.AddCommand(migrationDataCmd)
 	// Build up command
 	fetchFlags := migrationFetchCmd.Flags()
 	fetchFlags.String("db-url", "", "Fetches migrations from the database specified by the connection string (must be percent-encoded).")
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/supabase/cli/internal/postgres/upgrade"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/flags"
+	"github.com/supabase/cli/pkg/api"
+)
+
+var (
+	postgresGroupCmd = &cobra.Command{
+		GroupID: groupManagementAPI,
+		Use:     "postgres",
+		Short:   "Manage Postgres version of your project",
+	}
+
+	postgresUpgradeCmd = &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade Postgres major version of your project",
+	}
+
+	postgresUpgradeCheckCmd = &cobra.Command{
+		Use:   "check",
+		Short: "Check if your project is eligible for a Postgres upgrade",
+		Long:  "Check upgrade eligibility of the linked project, listing available target versions and any blocking issues.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return upgrade.RunCheck(cmd.Context(), flags.ProjectRef)
+		},
+	}
+
+	upgradeTargetVersion  string
+	upgradeReleaseChannel = utils.EnumFlag{
+		Allowed: []string{
+			string(api.UpgradeDatabaseBodyReleaseChannelGa),
+			string(api.UpgradeDatabaseBodyReleaseChannelBeta),
+			string(api.UpgradeDatabaseBodyReleaseChannelAlpha),
+			string(api.UpgradeDatabaseBodyReleaseChannelPreview),
+		},
+	}
+
+	postgresUpgradeStartCmd = &cobra.Command{
+		Use:   "start",
+		Short: "Start a Postgres upgrade",
+		Long: `Start a Postgres upgrade of the linked project and wait for it to complete.
+
+Your project will be offline while the upgrade is in progress.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return upgrade.RunStart(cmd.Context(), flags.ProjectRef, upgradeTargetVersion, upgradeReleaseChannel.Value, afero.NewOsFs())
+		},
+	}
+
+	upgradeTrackingId string
+	upgradeFollow     bool
+
+	postgresUpgradeStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show status of the latest Postgres upgrade",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return upgrade.RunStatus(cmd.Context(), flags.ProjectRef, upgradeTrackingId, upgradeFollow)
+		},
+	}
+)
+
+func init() {
+	postgresGroupCmd.PersistentFlags().StringVar(&flags.ProjectRef, "project-ref", "", "Project ref of the Supabase project.")
+	postgresUpgradeCmd.AddCommand(postgresUpgradeCheckCmd)
+	startFlags := postgresUpgradeStartCmd.Flags()
+	startFlags.StringVar(&upgradeTargetVersion, "target-version", "", "Postgres version to upgrade to. Defaults to the first eligible version.")
+	startFlags.Var(&upgradeReleaseChannel, "release-channel", "Release channel of the target version.")
+	postgresUpgradeCmd.AddCommand(postgresUpgradeStartCmd)
+	statusFlags := postgresUpgradeStatusCmd.Flags()
+	statusFlags.StringVar(&upgradeTrackingId, "tracking-id", "", "Tracking ID of the upgrade to show.")
+	statusFlags.BoolVarP(&upgradeFollow, "follow", "f", false, "Poll the upgrade status until it completes.")
+	postgresUpgradeCmd.AddCommand(postgresUpgradeStatusCmd)
+	postgresGroupCmd.AddCommand(postgresUpgradeCmd)
+	rootCmd.AddCommand(postgresGroupCmd)
+}
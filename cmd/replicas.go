@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/supabase/cli/internal/replicas/create"
+	"github.com/supabase/cli/internal/replicas/list"
+	"github.com/supabase/cli/internal/replicas/remove"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/flags"
+)
+
+var (
+	replicasCmd = &cobra.Command{
+		GroupID: groupManagementAPI,
+		Use:     "replicas",
+		Short:   "Manage read replicas of your project",
+	}
+
+	replicaRegion = utils.EnumFlag{
+		Allowed: utils.AwsRegions(),
+	}
+
+	replicaCreateCmd = &cobra.Command{
+		Use:   "create",
+		Short: "Create a read replica",
+		Long:  "Create a read replica of the linked project in the specified region.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return create.Run(cmd.Context(), replicaRegion.Value)
+		},
+	}
+
+	replicaRemoveCmd = &cobra.Command{
+		Use:   "remove <identifier>",
+		Short: "Remove a read replica",
+		Long:  "Remove a read replica by its database identifier, as shown by replicas list.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return remove.Run(cmd.Context(), args[0])
+		},
+	}
+
+	replicaListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List all databases of your project",
+		Long:  "List the primary and read replica databases of the linked project.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return list.Run(cmd.Context())
+		},
+	}
+)
+
+func init() {
+	replicaFlags := replicasCmd.PersistentFlags()
+	replicaFlags.StringVar(&flags.ProjectRef, "project-ref", "", "Project ref of the Supabase project.")
+	createFlags := replicaCreateCmd.Flags()
+	createFlags.Var(&replicaRegion, "region", "Select a region to deploy the read replica.")
+	cobra.CheckErr(replicaCreateCmd.MarkFlagRequired("region"))
+	replicasCmd.AddCommand(replicaCreateCmd)
+	replicasCmd.AddCommand(replicaRemoveCmd)
+	replicasCmd.AddCommand(replicaListCmd)
+	rootCmd.AddCommand(replicasCmd)
+}
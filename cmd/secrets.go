@@ -25,12 +25,14 @@ var (
 		},
 	}
 
+	secretsFunction string
+
 	secretsSetCmd = &cobra.Command{
 		Use:   "set <NAME=VALUE> ...",
 		Short: "Set a secret(s) on Supabase",
 		Long:  "Set a secret(s) to the linked Supabase project.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return set.Run(cmd.Context(), flags.ProjectRef, envFilePath, args, afero.NewOsFs())
+			return set.Run(cmd.Context(), flags.ProjectRef, secretsFunction, envFilePath, args, afero.NewOsFs())
 		},
 	}
 
@@ -47,6 +49,7 @@ var (
 func init() {
 	secretsCmd.PersistentFlags().StringVar(&flags.ProjectRef, "project-ref", "", "Project ref of the Supabase project.")
 	secretsSetCmd.Flags().StringVar(&envFilePath, "env-file", "", "Read secrets from a .env file.")
+	secretsSetCmd.Flags().StringVar(&secretsFunction, "function", "", "Read secrets scoped to a Function from its .env file and config. Hosted secrets remain readable by all Functions.")
 	secretsCmd.AddCommand(secretsListCmd)
 	secretsCmd.AddCommand(secretsSetCmd)
 	secretsCmd.AddCommand(secretsUnsetCmd)
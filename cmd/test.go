@@ -3,6 +3,7 @@ package cmd
 import (
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	functionsTest "github.com/supabase/cli/internal/functions/test"
 	"github.com/supabase/cli/internal/test/new"
 	"github.com/supabase/cli/internal/utils"
 )
@@ -20,6 +21,19 @@ var (
 		RunE:  dbTestCmd.RunE,
 	}
 
+	reporter = utils.EnumFlag{
+		Allowed: []string{functionsTest.ReporterTAP, functionsTest.ReporterJUnit},
+		Value:   functionsTest.ReporterTAP,
+	}
+
+	testFunctionsCmd = &cobra.Command{
+		Use:   "functions [path] ...",
+		Short: "Tests local Functions with Deno",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return functionsTest.Run(cmd.Context(), args, reporter.Value, envFilePath, afero.NewOsFs())
+		},
+	}
+
 	template = utils.EnumFlag{
 		Allowed: []string{new.TemplatePgTAP},
 		Value:   new.TemplatePgTAP,
@@ -43,6 +57,11 @@ func init() {
 	dbFlags.Bool("local", true, "Runs pgTAP tests on the local database.")
 	testDbCmd.MarkFlagsMutuallyExclusive("db-url", "linked", "local")
 	testCmd.AddCommand(testDbCmd)
+	// Build functions command
+	functionsFlags := testFunctionsCmd.Flags()
+	functionsFlags.Var(&reporter, "reporter", "Format of the test results.")
+	functionsFlags.StringVar(&envFilePath, "env-file", "", "Path to an env file to be populated to the test environment.")
+	testCmd.AddCommand(testFunctionsCmd)
 	// Build new command
 	newFlags := testNewCmd.Flags()
 	newFlags.VarP(&template, "template", "t", "Template framework to generate.")
@@ -0,0 +1,131 @@
+package advisors
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+	"github.com/supabase/cli/pkg/cast"
+)
+
+const (
+	TypeAll         = "all"
+	TypeSecurity    = "security"
+	TypePerformance = "performance"
+)
+
+var (
+	AllowedTypes = []string{
+		TypeAll,
+		TypeSecurity,
+		TypePerformance,
+	}
+	AllowedLevels = []string{
+		"info",
+		"warn",
+		"error",
+	}
+)
+
+type LintLevel int
+
+func toEnum(level string) LintLevel {
+	for i, curr := range AllowedLevels {
+		if strings.EqualFold(level, curr) {
+			return LintLevel(i)
+		}
+	}
+	return -1
+}
+
+func Run(ctx context.Context, projectRef, lintType, level, failOn string) error {
+	result, err := GetAdvisors(ctx, projectRef, lintType)
+	if err != nil {
+		return err
+	}
+	// Apply filtering based on the minimum level
+	minLevel := toEnum(level)
+	filtered := result.Lints[:0]
+	for _, lint := range result.Lints {
+		if toEnum(string(lint.Level)) >= minLevel {
+			filtered = append(filtered, lint)
+		}
+	}
+	result.Lints = filtered
+	if utils.OutputFormat.Value == utils.OutputPretty {
+		if len(result.Lints) == 0 {
+			fmt.Fprintln(os.Stderr, "No issues found")
+			return nil
+		}
+		if err := utils.RenderTable(ToMarkdown(result)); err != nil {
+			return err
+		}
+	} else if err := utils.EncodeOutput(utils.OutputFormat.Value, os.Stdout, result); err != nil {
+		return err
+	}
+	// Check for fail-on condition
+	if failOnLevel := toEnum(failOn); failOnLevel != -1 {
+		for _, lint := range result.Lints {
+			if toEnum(string(lint.Level)) >= failOnLevel {
+				return errors.Errorf("fail-on is set to %s, non-zero exit", AllowedLevels[failOnLevel])
+			}
+		}
+	}
+	return nil
+}
+
+func GetAdvisors(ctx context.Context, projectRef, lintType string) (api.V1ProjectAdvisorsResponse, error) {
+	var result api.V1ProjectAdvisorsResponse
+	if lintType == TypeAll || lintType == TypeSecurity {
+		resp, err := utils.GetSupabase().V1GetSecurityAdvisorsWithResponse(ctx, projectRef, &api.V1GetSecurityAdvisorsParams{})
+		if err != nil {
+			return result, errors.Errorf("failed to get security advisors: %w", err)
+		} else if resp.JSON200 == nil {
+			return result, errors.Errorf("unexpected security advisors status %d: %s", resp.StatusCode(), string(resp.Body))
+		}
+		result.Lints = append(result.Lints, resp.JSON200.Lints...)
+	}
+	if lintType == TypeAll || lintType == TypePerformance {
+		resp, err := utils.GetSupabase().V1GetPerformanceAdvisorsWithResponse(ctx, projectRef)
+		if err != nil {
+			return result, errors.Errorf("failed to get performance advisors: %w", err)
+		} else if resp.JSON200 == nil {
+			return result, errors.Errorf("unexpected performance advisors status %d: %s", resp.StatusCode(), string(resp.Body))
+		}
+		result.Lints = append(result.Lints, resp.JSON200.Lints...)
+	}
+	return result, nil
+}
+
+func ToMarkdown(result api.V1ProjectAdvisorsResponse) string {
+	table := `|LEVEL|CATEGORY|NAME|ENTITY|TITLE|
+|-|-|-|-|-|
+`
+	for _, lint := range result.Lints {
+		categories := make([]string, len(lint.Categories))
+		for i, c := range lint.Categories {
+			categories[i] = string(c)
+		}
+		entity := " "
+		if lint.Metadata != nil {
+			if name := cast.Val(lint.Metadata.Name, ""); len(name) > 0 {
+				entity = cast.Val(lint.Metadata.Schema, "public") + "." + name
+			} else {
+				entity = cast.Val(lint.Metadata.Entity, entity)
+			}
+		}
+		table += fmt.Sprintf(
+			"|`%s`|`%s`|`%s`|`%s`|%s|\n",
+			lint.Level,
+			strings.Join(categories, ","),
+			lint.Name,
+			strings.ReplaceAll(entity, "|", "\\|"),
+			strings.ReplaceAll(lint.Title, "|", "\\|"),
+		)
+	}
+	return table
+}
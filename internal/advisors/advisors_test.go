@@ -0,0 +1,113 @@
+package advisors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+	"github.com/supabase/cli/internal/testing/apitest"
+	"github.com/supabase/cli/internal/utils"
+)
+
+var mockLints = map[string]any{
+	"lints": []map[string]any{{
+		"name":        "rls_disabled_in_public",
+		"title":       "RLS Disabled in Public",
+		"level":       "ERROR",
+		"facing":      "EXTERNAL",
+		"categories":  []string{"SECURITY"},
+		"description": "Detects tables without row level security.",
+		"detail":      "Table `public.todos` is public, but RLS has not been enabled.",
+		"remediation": "https://supabase.com/docs/guides/database/database-linter",
+		"cache_key":   "rls_disabled_in_public_public_todos",
+		"metadata":    map[string]any{"schema": "public", "name": "todos", "type": "table"},
+	}},
+}
+
+func TestAdvisorsCommand(t *testing.T) {
+	// Setup valid access token
+	token := apitest.RandomAccessToken(t)
+	t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+	// Setup valid project ref
+	project := apitest.RandomProjectRef()
+
+	t.Run("lists all advisors", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/advisors/security").
+			Reply(http.StatusOK).
+			JSON(mockLints)
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/advisors/performance").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"lints": []any{}})
+		// Run test
+		err := Run(context.Background(), project, TypeAll, "info", "none")
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on fail-on level", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/advisors/security").
+			Reply(http.StatusOK).
+			JSON(mockLints)
+		// Run test
+		err := Run(context.Background(), project, TypeSecurity, "warn", "error")
+		// Check error
+		assert.ErrorContains(t, err, "fail-on is set to error, non-zero exit")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("ignores findings below min level", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/advisors/performance").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"lints": []map[string]any{{
+				"name":       "unindexed_foreign_keys",
+				"level":      "INFO",
+				"categories": []string{"PERFORMANCE"},
+			}}})
+		// Run test
+		err := Run(context.Background(), project, TypePerformance, "warn", "warn")
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on network error", func(t *testing.T) {
+		errNetwork := errors.New("network error")
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/advisors/security").
+			ReplyError(errNetwork)
+		// Run test
+		err := Run(context.Background(), project, TypeSecurity, "info", "none")
+		// Check error
+		assert.ErrorIs(t, err, errNetwork)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on service unavailable", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/advisors/performance").
+			Reply(http.StatusServiceUnavailable)
+		// Run test
+		err := Run(context.Background(), project, TypePerformance, "info", "none")
+		// Check error
+		assert.ErrorContains(t, err, "unexpected performance advisors status 503:")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+}
@@ -0,0 +1,63 @@
+package create
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func Run(ctx context.Context, projectRef, name string) error {
+	point, err := CreateRestorePoint(ctx, projectRef, name)
+	if err != nil {
+		return err
+	}
+	if utils.OutputFormat.Value != utils.OutputPretty {
+		return utils.EncodeOutput(utils.OutputFormat.Value, os.Stdout, point)
+	}
+	fmt.Fprintln(os.Stderr, "Created restore point:", utils.Aqua(point.Name))
+	return nil
+}
+
+// CreateRestorePoint creates a named restore point and waits for it to become available.
+func CreateRestorePoint(ctx context.Context, projectRef, name string) (api.V1RestorePointResponse, error) {
+	fmt.Fprintln(os.Stderr, "Creating restore point:", utils.Aqua(name))
+	resp, err := utils.GetSupabase().V1CreateRestorePointWithResponse(ctx, projectRef, api.V1RestorePointPostBody{Name: name})
+	if err != nil {
+		return api.V1RestorePointResponse{}, errors.Errorf("failed to create restore point: %w", err)
+	} else if resp.JSON201 == nil {
+		return api.V1RestorePointResponse{}, errors.Errorf("unexpected create restore point status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	return waitForRestorePoint(ctx, projectRef, *resp.JSON201)
+}
+
+// Used by unit tests
+var pollInterval = 3 * time.Second
+
+func waitForRestorePoint(ctx context.Context, projectRef string, point api.V1RestorePointResponse) (api.V1RestorePointResponse, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for point.Status == api.V1RestorePointResponseStatusPENDING {
+		select {
+		case <-ctx.Done():
+			return point, errors.New(ctx.Err())
+		case <-ticker.C:
+		}
+		params := api.V1GetRestorePointParams{Name: &point.Name}
+		resp, err := utils.GetSupabase().V1GetRestorePointWithResponse(ctx, projectRef, &params)
+		if err != nil {
+			return point, errors.Errorf("failed to get restore point: %w", err)
+		} else if resp.JSON200 == nil {
+			return point, errors.Errorf("unexpected get restore point status %d: %s", resp.StatusCode(), string(resp.Body))
+		}
+		point = *resp.JSON200
+	}
+	if point.Status != api.V1RestorePointResponseStatusAVAILABLE {
+		return point, errors.Errorf("restore point %s is %s", point.Name, point.Status)
+	}
+	return point, nil
+}
@@ -0,0 +1,70 @@
+package create
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+	"github.com/supabase/cli/internal/testing/apitest"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func TestCreateRestorePoint(t *testing.T) {
+	// Setup valid project ref
+	project := apitest.RandomProjectRef()
+	// Setup valid access token
+	token := apitest.RandomAccessToken(t)
+	t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+	pollInterval = time.Millisecond
+
+	t.Run("waits for restore point to be available", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/" + project + "/database/backups/restore-point").
+			JSON(api.V1RestorePointPostBody{Name: "test"}).
+			Reply(http.StatusCreated).
+			JSON(api.V1RestorePointResponse{Name: "test", Status: api.V1RestorePointResponseStatusPENDING})
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/"+project+"/database/backups/restore-point").
+			MatchParam("name", "test").
+			Reply(http.StatusOK).
+			JSON(api.V1RestorePointResponse{Name: "test", Status: api.V1RestorePointResponseStatusAVAILABLE})
+		// Run test
+		err := Run(context.Background(), project, "test")
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on failed restore point", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/" + project + "/database/backups/restore-point").
+			Reply(http.StatusCreated).
+			JSON(api.V1RestorePointResponse{Name: "test", Status: api.V1RestorePointResponseStatusFAILED})
+		// Run test
+		err := Run(context.Background(), project, "test")
+		// Check error
+		assert.ErrorContains(t, err, "restore point test is FAILED")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on service unavailable", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/" + project + "/database/backups/restore-point").
+			Reply(http.StatusServiceUnavailable)
+		// Run test
+		err := Run(context.Background(), project, "test")
+		// Check error
+		assert.ErrorContains(t, err, "unexpected create restore point status 503:")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+}
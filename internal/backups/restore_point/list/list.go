@@ -0,0 +1,37 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func Run(ctx context.Context, projectRef, name string) error {
+	params := api.V1GetRestorePointParams{}
+	if len(name) > 0 {
+		params.Name = &name
+	}
+	resp, err := utils.GetSupabase().V1GetRestorePointWithResponse(ctx, projectRef, &params)
+	if err != nil {
+		return errors.Errorf("failed to list restore points: %w", err)
+	} else if resp.JSON200 == nil {
+		return errors.Errorf("unexpected list restore points status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	if utils.OutputFormat.Value == utils.OutputPretty {
+		table := `|NAME|STATUS|
+|-|-|
+`
+		table += fmt.Sprintf(
+			"|`%s`|`%s`|\n",
+			strings.ReplaceAll(resp.JSON200.Name, "|", "\\|"),
+			resp.JSON200.Status,
+		)
+		return utils.RenderTable(table)
+	}
+	return utils.EncodeOutput(utils.OutputFormat.Value, os.Stdout, *resp.JSON200)
+}
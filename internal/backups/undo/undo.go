@@ -0,0 +1,29 @@
+package undo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func Run(ctx context.Context, projectRef, name string) error {
+	title := fmt.Sprintf("Do you want to undo all changes made to project %s since restore point %s?", utils.Aqua(projectRef), utils.Aqua(name))
+	if shouldUndo, err := utils.NewConsole().PromptYesNo(ctx, title, false); err != nil {
+		return err
+	} else if !shouldUndo {
+		return errors.New(context.Canceled)
+	}
+	resp, err := utils.GetSupabase().V1UndoWithResponse(ctx, projectRef, api.V1UndoBody{Name: name})
+	if err != nil {
+		return errors.Errorf("failed to undo: %w", err)
+	} else if resp.StatusCode() != http.StatusCreated {
+		return errors.Errorf("unexpected undo status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	fmt.Fprintln(os.Stderr, "Started restoring to restore point:", utils.Aqua(name))
+	return nil
+}
@@ -121,7 +121,7 @@ func Run(ctx context.Context, starter StarterTemplate, fsys afero.Fs, options ..
 	}
 	policy.Reset()
 	if err := backoff.RetryNotify(func() error {
-		return push.Run(ctx, false, false, true, true, config, fsys)
+		return push.Run(ctx, false, false, false, true, true, "", config, fsys)
 	}, policy, utils.NewErrorCallback()); err != nil {
 		return err
 	}
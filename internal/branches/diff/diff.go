@@ -0,0 +1,38 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/branches/pause"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func Run(ctx context.Context, branchId string, schema []string) error {
+	projectRef, err := pause.GetBranchProjectRef(ctx, branchId)
+	if err != nil {
+		return err
+	}
+	var params api.V1DiffABranchParams
+	if len(schema) > 0 {
+		included := strings.Join(schema, ",")
+		params.IncludedSchemas = &included
+	}
+	resp, err := utils.GetSupabase().V1DiffABranchWithResponse(ctx, projectRef, &params)
+	if err != nil {
+		return errors.Errorf("failed to diff branch: %w", err)
+	} else if resp.StatusCode() != http.StatusOK {
+		return errors.Errorf("unexpected diff branch status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	if len(strings.TrimSpace(string(resp.Body))) == 0 {
+		fmt.Fprintln(os.Stderr, "No schema changes found")
+		return nil
+	}
+	fmt.Print(string(resp.Body))
+	return nil
+}
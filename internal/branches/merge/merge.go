@@ -0,0 +1,70 @@
+package merge
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/branches/pause"
+	"github.com/supabase/cli/internal/branches/runs"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func Run(ctx context.Context, branchId, migrationVersion string) error {
+	projectRef, err := pause.GetBranchProjectRef(ctx, branchId)
+	if err != nil {
+		return err
+	}
+	var body api.BranchActionBody
+	if len(migrationVersion) > 0 {
+		body.MigrationVersion = &migrationVersion
+	}
+	resp, err := utils.GetSupabase().V1MergeABranchWithResponse(ctx, projectRef, body)
+	if err != nil {
+		return errors.Errorf("failed to merge branch: %w", err)
+	} else if resp.JSON201 == nil {
+		return errors.Errorf("unexpected merge branch status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	fmt.Fprintln(os.Stderr, "Started merging branch:", utils.Aqua(projectRef))
+	if err := findActionRun(ctx, projectRef, resp.JSON201.WorkflowRunId); err != nil {
+		return err
+	}
+	if err := runs.WaitForRun(ctx, projectRef, resp.JSON201.WorkflowRunId); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "Finished merging branch:", utils.Aqua(projectRef))
+	return nil
+}
+
+// The merge response only returns a workflow run id, so confirm that it is listed as an
+// action run of the branch before polling its status.
+func findActionRun(ctx context.Context, projectRef, workflowRunId string) error {
+	ticker := time.NewTicker(runs.PollInterval)
+	defer ticker.Stop()
+	deadline := time.Now().Add(runs.PendingTimeout)
+	for {
+		resp, err := utils.GetSupabase().V1ListActionRunsWithResponse(ctx, projectRef, &api.V1ListActionRunsParams{})
+		if err != nil {
+			return errors.Errorf("failed to list action runs: %w", err)
+		} else if resp.JSON200 == nil {
+			return errors.Errorf("unexpected list action runs status %d: %s", resp.StatusCode(), string(resp.Body))
+		}
+		for _, run := range *resp.JSON200 {
+			if run.Id == workflowRunId {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			utils.CmdSuggestion = fmt.Sprintf("Run %s to check on the merge.", utils.Aqua("supabase branches runs list"))
+			return errors.Errorf("action run not found for merge workflow: %s", workflowRunId)
+		}
+		select {
+		case <-ctx.Done():
+			return errors.New(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
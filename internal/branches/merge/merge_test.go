@@ -0,0 +1,110 @@
+package merge
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+	"github.com/supabase/cli/internal/branches/runs"
+	"github.com/supabase/cli/internal/testing/apitest"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func TestMergeCommand(t *testing.T) {
+	// Setup valid access token
+	token := apitest.RandomAccessToken(t)
+	t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+	// Setup valid branch ref
+	ref := apitest.RandomProjectRef()
+	runs.PollInterval = time.Millisecond
+	runs.PendingTimeout = 10 * time.Millisecond
+
+	t.Run("merges branch and waits for run", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/branches/" + ref + "/merge").
+			Reply(http.StatusCreated).
+			JSON(api.BranchUpdateResponse{WorkflowRunId: "run-1"})
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions").
+			Reply(http.StatusOK).
+			JSON([]map[string]any{{"id": "run-1"}})
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions/run-1").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"run_steps": []map[string]any{
+				{"name": "migrate", "status": "RUNNING"},
+			}})
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions/run-1").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"run_steps": []map[string]any{
+				{"name": "migrate", "status": "EXITED"},
+			}})
+		// Run test
+		err := Run(context.Background(), ref, "")
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on failed step", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/branches/" + ref + "/merge").
+			Reply(http.StatusCreated).
+			JSON(api.BranchUpdateResponse{WorkflowRunId: "run-2"})
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions").
+			Reply(http.StatusOK).
+			JSON([]map[string]any{{"id": "run-2"}})
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions/run-2").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"id": "run-2", "run_steps": []map[string]any{
+				{"name": "migrate", "status": "DEAD"},
+			}})
+		// Run test
+		err := Run(context.Background(), ref, "20240101000000")
+		// Check error
+		assert.ErrorContains(t, err, "action run run-2 failed")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on unknown workflow run", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/branches/" + ref + "/merge").
+			Reply(http.StatusCreated).
+			JSON(api.BranchUpdateResponse{WorkflowRunId: "workflow-1"})
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions").
+			Persist().
+			Reply(http.StatusOK).
+			JSON([]map[string]any{{"id": "run-1"}})
+		// Run test
+		err := Run(context.Background(), ref, "")
+		// Check error
+		assert.ErrorContains(t, err, "action run not found for merge workflow: workflow-1")
+	})
+
+	t.Run("throws error on service unavailable", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/branches/" + ref + "/merge").
+			Reply(http.StatusServiceUnavailable)
+		// Run test
+		err := Run(context.Background(), ref, "")
+		// Check error
+		assert.ErrorContains(t, err, "unexpected merge branch status 503:")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+}
@@ -0,0 +1,32 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/branches/pause"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func Run(ctx context.Context, branchId, migrationVersion string) error {
+	projectRef, err := pause.GetBranchProjectRef(ctx, branchId)
+	if err != nil {
+		return err
+	}
+	var body api.BranchActionBody
+	if len(migrationVersion) > 0 {
+		body.MigrationVersion = &migrationVersion
+	}
+	resp, err := utils.GetSupabase().V1PushABranchWithResponse(ctx, projectRef, body)
+	if err != nil {
+		return errors.Errorf("failed to push branch: %w", err)
+	} else if resp.JSON201 == nil {
+		return errors.Errorf("unexpected push branch status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	fmt.Fprintln(os.Stderr, "Started pushing branch:", utils.Aqua(projectRef))
+	fmt.Fprintln(os.Stderr, "Workflow run ID:", resp.JSON201.WorkflowRunId)
+	return nil
+}
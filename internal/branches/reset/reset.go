@@ -0,0 +1,38 @@
+package reset
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/branches/pause"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func Run(ctx context.Context, branchId, migrationVersion string) error {
+	projectRef, err := pause.GetBranchProjectRef(ctx, branchId)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("Do you want to reset all data on branch %s?", utils.Aqua(projectRef))
+	if shouldReset, err := utils.NewConsole().PromptYesNo(ctx, title, false); err != nil {
+		return err
+	} else if !shouldReset {
+		return errors.New(context.Canceled)
+	}
+	var body api.BranchActionBody
+	if len(migrationVersion) > 0 {
+		body.MigrationVersion = &migrationVersion
+	}
+	resp, err := utils.GetSupabase().V1ResetABranchWithResponse(ctx, projectRef, body)
+	if err != nil {
+		return errors.Errorf("failed to reset branch: %w", err)
+	} else if resp.JSON201 == nil {
+		return errors.Errorf("unexpected reset branch status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	fmt.Fprintln(os.Stderr, "Started resetting branch:", utils.Aqua(projectRef))
+	fmt.Fprintln(os.Stderr, "Workflow run ID:", resp.JSON201.WorkflowRunId)
+	return nil
+}
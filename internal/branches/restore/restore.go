@@ -0,0 +1,26 @@
+package restore
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/branches/pause"
+	"github.com/supabase/cli/internal/utils"
+)
+
+func Run(ctx context.Context, branchId string) error {
+	projectRef, err := pause.GetBranchProjectRef(ctx, branchId)
+	if err != nil {
+		return err
+	}
+	resp, err := utils.GetSupabase().V1RestoreABranchWithResponse(ctx, projectRef)
+	if err != nil {
+		return errors.Errorf("failed to restore branch: %w", err)
+	} else if resp.JSON200 == nil {
+		return errors.Errorf("unexpected restore branch status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	fmt.Fprintln(os.Stderr, "Restored branch:", utils.Aqua(projectRef))
+	return nil
+}
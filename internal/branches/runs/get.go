@@ -0,0 +1,147 @@
+package runs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+)
+
+const (
+	StatusPending   = "PENDING"
+	StatusRunning   = "RUNNING"
+	StatusCompleted = "COMPLETED"
+	StatusFailed    = "FAILED"
+)
+
+func RunGet(ctx context.Context, projectRef, runId string) error {
+	run, err := GetRun(ctx, projectRef, runId)
+	if err != nil {
+		return err
+	}
+	if utils.OutputFormat.Value != utils.OutputPretty {
+		if err := utils.EncodeOutput(utils.OutputFormat.Value, os.Stdout, run); err != nil {
+			return err
+		}
+	} else if err := renderRun(run); err != nil {
+		return err
+	}
+	// Exit non-zero on failed runs so that scripts can gate on the result
+	if err := checkStatus(run); !errors.Is(err, errRunning) {
+		return err
+	}
+	return nil
+}
+
+func renderRun(run api.ActionRunResponse) error {
+	fmt.Fprintln(os.Stderr, "Run status:", utils.Aqua(GetStatus(run)))
+	table := `|STEP|STATUS|CREATED AT (UTC)|UPDATED AT (UTC)|
+|-|-|-|-|
+`
+	for _, s := range run.RunSteps {
+		table += fmt.Sprintf(
+			"|`%s`|`%s`|`%s`|`%s`|\n",
+			s.Name,
+			s.Status,
+			utils.FormatTimestamp(s.CreatedAt),
+			utils.FormatTimestamp(s.UpdatedAt),
+		)
+	}
+	return utils.RenderTable(table)
+}
+
+func GetRun(ctx context.Context, projectRef, runId string) (api.ActionRunResponse, error) {
+	resp, err := utils.GetSupabase().V1GetActionRunWithResponse(ctx, projectRef, runId)
+	if err != nil {
+		return api.ActionRunResponse{}, errors.Errorf("failed to get action run: %w", err)
+	} else if resp.JSON200 == nil {
+		return api.ActionRunResponse{}, errors.Errorf("unexpected get action run status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	return *resp.JSON200, nil
+}
+
+// GetStatus summarises the status of all steps in an action run.
+func GetStatus(run api.ActionRunResponse) string {
+	if len(run.RunSteps) == 0 {
+		return StatusPending
+	}
+	status := StatusCompleted
+	for _, s := range run.RunSteps {
+		status = mergeStatus(status, s.Status)
+	}
+	return status
+}
+
+func mergeStatus(status string, step api.ActionRunResponseRunStepsStatus) string {
+	switch step {
+	case api.ActionRunResponseRunStepsStatusDEAD:
+		return StatusFailed
+	case api.ActionRunResponseRunStepsStatusEXITED:
+		return status
+	}
+	if status == StatusFailed {
+		return status
+	}
+	return StatusRunning
+}
+
+// Used by unit tests of commands that wait for action runs
+var (
+	PollInterval   = 5 * time.Second
+	PendingTimeout = 5 * time.Minute
+)
+
+// WaitForRun polls an action run until it completes, printing the status of each step.
+func WaitForRun(ctx context.Context, projectRef, runId string) error {
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+	reported := map[api.ActionRunResponseRunStepsName]api.ActionRunResponseRunStepsStatus{}
+	deadline := time.Now().Add(PendingTimeout)
+	for {
+		run, err := GetRun(ctx, projectRef, runId)
+		if err != nil {
+			return err
+		} else if err := checkStarted(run, deadline); err != nil {
+			return err
+		}
+		for _, s := range run.RunSteps {
+			if reported[s.Name] != s.Status {
+				reported[s.Name] = s.Status
+				fmt.Fprintf(os.Stderr, "Step %s: %s\n", utils.Aqua(string(s.Name)), s.Status)
+			}
+		}
+		if err := checkStatus(run); !errors.Is(err, errRunning) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return errors.New(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+var errRunning = errors.New("action run is still running")
+
+func checkStatus(run api.ActionRunResponse) error {
+	switch GetStatus(run) {
+	case StatusCompleted:
+		return nil
+	case StatusFailed:
+		return errors.Errorf("action run %s failed", run.Id)
+	}
+	return errRunning
+}
+
+// Runs without steps never reach a terminal status, so stop waiting after a grace period.
+func checkStarted(run api.ActionRunResponse, deadline time.Time) error {
+	if len(run.RunSteps) > 0 || time.Now().Before(deadline) {
+		return nil
+	}
+	utils.CmdSuggestion = fmt.Sprintf("Run %s to check on the action run later.", utils.Aqua("supabase branches runs get "+run.Id))
+	return errors.Errorf("action run %s has not started any steps after %s", run.Id, PendingTimeout)
+}
@@ -0,0 +1,78 @@
+package runs
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+	"github.com/supabase/cli/internal/testing/apitest"
+	"github.com/supabase/cli/internal/utils"
+)
+
+func TestGetCommand(t *testing.T) {
+	// Setup valid access token
+	token := apitest.RandomAccessToken(t)
+	t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+	// Setup valid branch ref
+	ref := apitest.RandomProjectRef()
+
+	t.Run("prints running run", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions/run-1").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"id": "run-1", "run_steps": []map[string]any{
+				{"name": "clone", "status": "EXITED"},
+				{"name": "migrate", "status": "RUNNING"},
+			}})
+		// Run test
+		err := RunGet(context.Background(), ref, "run-1")
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on failed run", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions/run-2").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"id": "run-2", "run_steps": []map[string]any{
+				{"name": "migrate", "status": "DEAD"},
+			}})
+		// Run test
+		err := RunGet(context.Background(), ref, "run-2")
+		// Check error
+		assert.ErrorContains(t, err, "action run run-2 failed")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+}
+
+func TestWaitForRun(t *testing.T) {
+	// Setup valid access token
+	token := apitest.RandomAccessToken(t)
+	t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+	// Setup valid branch ref
+	ref := apitest.RandomProjectRef()
+	PollInterval = time.Millisecond
+	PendingTimeout = 10 * time.Millisecond
+
+	t.Run("throws error on run without steps", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions/run-1").
+			Persist().
+			Reply(http.StatusOK).
+			JSON(map[string]any{"id": "run-1", "run_steps": []map[string]any{}})
+		// Run test
+		err := WaitForRun(context.Background(), ref, "run-1")
+		// Check error
+		assert.ErrorContains(t, err, "action run run-1 has not started any steps after 10ms")
+	})
+}
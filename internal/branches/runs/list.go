@@ -0,0 +1,74 @@
+package runs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+	"github.com/supabase/cli/pkg/cast"
+)
+
+func RunList(ctx context.Context, projectRef string, limit uint) error {
+	params := api.V1ListActionRunsParams{Limit: cast.Ptr(float32(limit))}
+	resp, err := utils.GetSupabase().V1ListActionRunsWithResponse(ctx, projectRef, &params)
+	if err != nil {
+		return errors.Errorf("failed to list action runs: %w", err)
+	} else if resp.JSON200 == nil {
+		return errors.Errorf("unexpected list action runs status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	if utils.OutputFormat.Value != utils.OutputPretty {
+		return utils.EncodeOutput(utils.OutputFormat.Value, os.Stdout, *resp.JSON200)
+	}
+	table := `|ID|STATUS|STEPS|CREATED AT (UTC)|UPDATED AT (UTC)|
+|-|-|-|-|-|
+`
+	for _, run := range *resp.JSON200 {
+		var steps []string
+		status := StatusCompleted
+		for _, s := range run.RunSteps {
+			steps = append(steps, fmt.Sprintf("%s: %s", s.Name, s.Status))
+			status = mergeStatus(status, api.ActionRunResponseRunStepsStatus(s.Status))
+		}
+		if len(run.RunSteps) == 0 {
+			status = StatusPending
+		}
+		table += fmt.Sprintf(
+			"|`%s`|`%s`|`%s`|`%s`|`%s`|\n",
+			run.Id,
+			status,
+			strings.Join(steps, ", "),
+			utils.FormatTimestamp(run.CreatedAt),
+			utils.FormatTimestamp(run.UpdatedAt),
+		)
+	}
+	if err := utils.RenderTable(table); err != nil {
+		return err
+	}
+	if total, err := CountRuns(ctx, projectRef); err != nil {
+		fmt.Fprintln(utils.GetDebugLogger(), err)
+	} else if total > len(*resp.JSON200) {
+		fmt.Fprintf(os.Stderr, "Showing %d of %d runs\n", len(*resp.JSON200), total)
+	}
+	return nil
+}
+
+// CountRuns returns the total number of action runs of a branch.
+func CountRuns(ctx context.Context, projectRef string) (int, error) {
+	resp, err := utils.GetSupabase().V1CountActionRunsWithResponse(ctx, projectRef)
+	if err != nil {
+		return 0, errors.Errorf("failed to count action runs: %w", err)
+	} else if resp.StatusCode() != http.StatusOK {
+		return 0, errors.Errorf("unexpected count action runs status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	total, err := strconv.Atoi(resp.HTTPResponse.Header.Get("X-Total-Count"))
+	if err != nil {
+		return 0, errors.Errorf("failed to parse total count: %w", err)
+	}
+	return total, nil
+}
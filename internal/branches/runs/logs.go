@@ -0,0 +1,74 @@
+package runs
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/utils"
+)
+
+func RunLogs(ctx context.Context, projectRef, runId string, follow bool, w io.Writer) error {
+	if !follow {
+		run, err := GetRun(ctx, projectRef, runId)
+		if err != nil {
+			return err
+		}
+		logs, err := GetLogs(ctx, projectRef, runId)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(logs); err != nil {
+			return errors.Errorf("failed to write logs: %w", err)
+		}
+		if err := checkStatus(run); !errors.Is(err, errRunning) {
+			return err
+		}
+		return nil
+	}
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+	var printed int
+	deadline := time.Now().Add(PendingTimeout)
+	for {
+		// Check status before fetching logs so that no output is lost on completion
+		run, err := GetRun(ctx, projectRef, runId)
+		if err != nil {
+			return err
+		} else if err := checkStarted(run, deadline); err != nil {
+			return err
+		}
+		logs, err := GetLogs(ctx, projectRef, runId)
+		if err != nil {
+			return err
+		}
+		// Logs are returned in full, so only write the unseen suffix
+		if len(logs) < printed {
+			printed = 0
+		}
+		if _, err := w.Write(logs[printed:]); err != nil {
+			return errors.Errorf("failed to write logs: %w", err)
+		}
+		printed = len(logs)
+		if err := checkStatus(run); !errors.Is(err, errRunning) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return errors.New(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func GetLogs(ctx context.Context, projectRef, runId string) ([]byte, error) {
+	resp, err := utils.GetSupabase().V1GetActionRunLogsWithResponse(ctx, projectRef, runId)
+	if err != nil {
+		return nil, errors.Errorf("failed to get action run logs: %w", err)
+	} else if resp.StatusCode() != http.StatusOK {
+		return nil, errors.Errorf("unexpected get action run logs status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	return resp.Body, nil
+}
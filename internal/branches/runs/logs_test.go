@@ -0,0 +1,117 @@
+package runs
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+	"github.com/supabase/cli/internal/testing/apitest"
+	"github.com/supabase/cli/internal/utils"
+)
+
+func TestLogsCommand(t *testing.T) {
+	// Setup valid access token
+	token := apitest.RandomAccessToken(t)
+	t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+	// Setup valid branch ref
+	ref := apitest.RandomProjectRef()
+	PollInterval = time.Millisecond
+
+	t.Run("streams logs until run completes", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions/run-1").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"id": "run-1", "run_steps": []map[string]any{
+				{"name": "migrate", "status": "RUNNING"},
+			}})
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions/run-1/logs").
+			Reply(http.StatusOK).
+			BodyString("clone\n")
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions/run-1").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"id": "run-1", "run_steps": []map[string]any{
+				{"name": "migrate", "status": "EXITED"},
+			}})
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions/run-1/logs").
+			Reply(http.StatusOK).
+			BodyString("clone\nmigrate\n")
+		// Run test
+		var out bytes.Buffer
+		err := RunLogs(context.Background(), ref, "run-1", true, &out)
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, "clone\nmigrate\n", out.String())
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on failed run", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions/run-2").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"id": "run-2", "run_steps": []map[string]any{
+				{"name": "clone", "status": "EXITED"},
+				{"name": "migrate", "status": "DEAD"},
+			}})
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions/run-2/logs").
+			Reply(http.StatusOK).
+			BodyString("error: relation already exists\n")
+		// Run test
+		var out bytes.Buffer
+		err := RunLogs(context.Background(), ref, "run-2", true, &out)
+		// Check error
+		assert.ErrorContains(t, err, "action run run-2 failed")
+		assert.Equal(t, "error: relation already exists\n", out.String())
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("prints logs of failed run once", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions/run-2").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"id": "run-2", "run_steps": []map[string]any{
+				{"name": "migrate", "status": "DEAD"},
+			}})
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions/run-2/logs").
+			Reply(http.StatusOK).
+			BodyString("error: relation already exists\n")
+		// Run test
+		var out bytes.Buffer
+		err := RunLogs(context.Background(), ref, "run-2", false, &out)
+		// Check error
+		assert.ErrorContains(t, err, "action run run-2 failed")
+		assert.Equal(t, "error: relation already exists\n", out.String())
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on service unavailable", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions/run-3").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"id": "run-3"})
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + ref + "/actions/run-3/logs").
+			Reply(http.StatusServiceUnavailable)
+		// Run test
+		err := RunLogs(context.Background(), ref, "run-3", false, &bytes.Buffer{})
+		// Check error
+		assert.ErrorContains(t, err, "unexpected get action run logs status 503:")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+}
@@ -0,0 +1,98 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/parser"
+)
+
+// CompareDiffer runs both engines against the same databases and reports statements that are
+// only generated by one of them. The output of the first engine is returned.
+func CompareDiffer(name, other string, w io.Writer) (DiffFunc, error) {
+	primary, err := GetEngine(name)
+	if err != nil {
+		return nil, err
+	}
+	secondary, err := GetEngine(other)
+	if err != nil {
+		return nil, err
+	}
+	name, other = engineOrDefault(name), engineOrDefault(other)
+	return func(ctx context.Context, source, target pgconn.Config, schema []string, options ...func(*pgx.ConnConfig)) (string, error) {
+		fmt.Fprintln(w, "Diffing schemas with "+utils.Aqua(name)+"...")
+		out, err := primary.Diff(ctx, source, target, schema, options...)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintln(w, "Diffing schemas with "+utils.Aqua(other)+"...")
+		result, err := secondary.Diff(ctx, source, target, schema, options...)
+		if err != nil {
+			return "", err
+		}
+		onlyLeft, onlyRight := CompareStatements(out, result)
+		if len(onlyLeft) == 0 && len(onlyRight) == 0 {
+			fmt.Fprintln(w, "Both engines generated the same statements.")
+			return out, nil
+		}
+		for _, s := range []struct {
+			engine     string
+			statements []string
+		}{{name, onlyLeft}, {other, onlyRight}} {
+			fmt.Fprintf(w, "Statements only generated by %s (%d):\n", utils.Aqua(s.engine), len(s.statements))
+			for _, line := range s.statements {
+				fmt.Fprintln(w, utils.Yellow(line+";"))
+			}
+		}
+		return out, nil
+	}, nil
+}
+
+// CompareStatements returns the statements that only appear in one of the diff outputs,
+// ignoring order, comments, and differences in whitespace.
+func CompareStatements(left, right string) ([]string, []string) {
+	a, b := normaliseStatements(left), normaliseStatements(right)
+	return subtractStatements(a, b), subtractStatements(b, a)
+}
+
+func normaliseStatements(out string) []string {
+	lines, err := parser.SplitAndTrim(strings.NewReader(out))
+	if err != nil {
+		return nil
+	}
+	var result []string
+	for _, line := range lines {
+		var body []string
+		for _, l := range strings.Split(line, "\n") {
+			if l = strings.TrimSpace(l); len(l) > 0 && !strings.HasPrefix(l, "--") {
+				body = append(body, l)
+			}
+		}
+		stat := strings.TrimSuffix(strings.Join(strings.Fields(strings.Join(body, " ")), " "), ";")
+		if len(stat) > 0 {
+			result = append(result, stat)
+		}
+	}
+	return result
+}
+
+func subtractStatements(left, right []string) []string {
+	count := map[string]int{}
+	for _, s := range right {
+		count[s]++
+	}
+	var result []string
+	for _, s := range left {
+		if count[s] > 0 {
+			count[s]--
+		} else {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/spf13/afero"
+	"github.com/supabase/cli/internal/db/start"
+	"github.com/supabase/cli/internal/utils"
+)
+
+var ErrNoDeclaredSchema = errors.New("No declarative schema files found. Set schema_paths under [db.migrations] in config.toml.")
+
+// DiffDeclared applies declarative schema files to a clean shadow database, then diffs the
+// target database against it. The output migrates the target database to the declared schema.
+func DiffDeclared(ctx context.Context, schema []string, config pgconn.Config, w io.Writer, fsys afero.Fs, differ DiffFunc, options ...func(*pgx.ConnConfig)) (string, error) {
+	declared, err := utils.Config.Db.Migrations.SchemaPaths.Files(afero.NewIOFS(fsys))
+	if err != nil {
+		return "", err
+	} else if len(declared) == 0 {
+		return "", errors.New(ErrNoDeclaredSchema)
+	}
+	fmt.Fprintln(w, "Creating shadow database...")
+	shadow, err := CreateShadowDatabase(ctx, utils.Config.Db.ShadowPort)
+	if err != nil {
+		return "", err
+	}
+	defer utils.DockerRemove(shadow)
+	if err := start.WaitForHealthyService(ctx, utils.Config.Db.HealthTimeout, shadow); err != nil {
+		return "", err
+	}
+	if err := prepareShadowDatabase(ctx, shadow, fsys, options...); err != nil {
+		return "", err
+	}
+	// Template database is cloned before any migration is applied
+	declaredConfig := pgconn.Config{
+		Host:     utils.Config.Hostname,
+		Port:     utils.Config.Db.ShadowPort,
+		User:     "postgres",
+		Password: utils.Config.Db.Password,
+		Database: "contrib_regression",
+	}
+	if err := migrateBaseDatabase(ctx, declaredConfig, declared, fsys, options...); err != nil {
+		return "", err
+	}
+	if len(schema) > 0 {
+		fmt.Fprintln(w, "Diffing schemas:", strings.Join(schema, ","))
+	} else {
+		fmt.Fprintln(w, "Diffing schemas...")
+	}
+	return differ(ctx, config, declaredConfig, schema, options...)
+}
+
+func prepareShadowDatabase(ctx context.Context, container string, fsys afero.Fs, options ...func(*pgx.ConnConfig)) error {
+	conn, err := ConnectShadowDatabase(ctx, 10*time.Second, options...)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+	return setupShadowDatabase(ctx, conn, container, fsys)
+}
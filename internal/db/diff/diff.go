@@ -31,16 +31,17 @@ import (
 type DiffFunc func(context.Context, pgconn.Config, pgconn.Config, []string, ...func(*pgx.ConnConfig)) (string, error)
 
 func Run(ctx context.Context, schema []string, file string, config pgconn.Config, differ DiffFunc, fsys afero.Fs, options ...func(*pgx.ConnConfig)) (err error) {
-	out, err := DiffDatabase(ctx, schema, config, os.Stderr, fsys, differ, options...)
+	// Only generate down scripts when saving to a migration file
+	out, down, err := diffDatabase(ctx, schema, config, os.Stderr, fsys, differ, len(file) > 0, options...)
 	if err != nil {
 		return err
 	}
 	branch := keys.GetGitBranch(fsys)
 	fmt.Fprintln(os.Stderr, "Finished "+utils.Aqua("supabase db diff")+" on branch "+utils.Aqua(branch)+".\n")
-	if err := SaveDiff(out, file, fsys); err != nil {
+	if err := saveDiff(out, down, file, fsys); err != nil {
 		return err
 	}
-	drops := findDropStatements(out)
+	drops := FindDropStatements(out)
 	if len(drops) > 0 {
 		fmt.Fprintln(os.Stderr, "Found drop statements in schema diff. Please double check if these are expected:")
 		fmt.Fprintln(os.Stderr, utils.Yellow(strings.Join(drops, "\n")))
@@ -75,7 +76,7 @@ func loadDeclaredSchemas(fsys afero.Fs) ([]string, error) {
 // https://github.com/djrobstep/migra/blob/master/migra/statements.py#L6
 var dropStatementPattern = regexp.MustCompile(`(?i)drop\s+`)
 
-func findDropStatements(out string) []string {
+func FindDropStatements(out string) []string {
 	lines, err := parser.SplitAndTrim(strings.NewReader(out))
 	if err != nil {
 		return nil
@@ -127,27 +128,39 @@ func MigrateShadowDatabase(ctx context.Context, container string, fsys afero.Fs,
 		return err
 	}
 	defer conn.Close(context.Background())
+	if err := setupShadowDatabase(ctx, conn, container, fsys); err != nil {
+		return err
+	}
+	return migration.ApplyMigrations(ctx, migrations, conn, afero.NewIOFS(fsys))
+}
+
+func setupShadowDatabase(ctx context.Context, conn *pgx.Conn, container string, fsys afero.Fs) error {
 	if err := start.SetupDatabase(ctx, conn, container[:12], os.Stderr, fsys); err != nil {
 		return err
 	}
 	if _, err := conn.Exec(ctx, CREATE_TEMPLATE); err != nil {
 		return errors.Errorf("failed to create template database: %w", err)
 	}
-	return migration.ApplyMigrations(ctx, migrations, conn, afero.NewIOFS(fsys))
+	return nil
 }
 
 func DiffDatabase(ctx context.Context, schema []string, config pgconn.Config, w io.Writer, fsys afero.Fs, differ DiffFunc, options ...func(*pgx.ConnConfig)) (string, error) {
+	out, _, err := diffDatabase(ctx, schema, config, w, fsys, differ, false, options...)
+	return out, err
+}
+
+func diffDatabase(ctx context.Context, schema []string, config pgconn.Config, w io.Writer, fsys afero.Fs, differ DiffFunc, withRollback bool, options ...func(*pgx.ConnConfig)) (string, string, error) {
 	fmt.Fprintln(w, "Creating shadow database...")
 	shadow, err := CreateShadowDatabase(ctx, utils.Config.Db.ShadowPort)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 	defer utils.DockerRemove(shadow)
 	if err := start.WaitForHealthyService(ctx, utils.Config.Db.HealthTimeout, shadow); err != nil {
-		return "", err
+		return "", "", err
 	}
 	if err := MigrateShadowDatabase(ctx, shadow, fsys, options...); err != nil {
-		return "", err
+		return "", "", err
 	}
 	shadowConfig := pgconn.Config{
 		Host:     utils.Config.Hostname,
@@ -161,10 +174,10 @@ func DiffDatabase(ctx context.Context, schema []string, config pgconn.Config, w
 			config = shadowConfig
 			config.Database = "contrib_regression"
 			if err := migrateBaseDatabase(ctx, config, declared, fsys, options...); err != nil {
-				return "", err
+				return "", "", err
 			}
 		} else if err != nil {
-			return "", err
+			return "", "", err
 		}
 	}
 	// Load all user defined schemas
@@ -173,7 +186,49 @@ func DiffDatabase(ctx context.Context, schema []string, config pgconn.Config, w
 	} else {
 		fmt.Fprintln(w, "Diffing schemas...")
 	}
-	return differ(ctx, shadowConfig, config, schema, options...)
+	out, err := differ(ctx, shadowConfig, config, schema, options...)
+	if err != nil || !withRollback || len(out) < 2 {
+		return out, "", err
+	}
+	// A broken down script should not prevent saving the migration itself
+	down, err := diffRollback(ctx, out, shadowConfig, config, schema, w, differ, options...)
+	if err != nil {
+		fmt.Fprintln(w, utils.Yellow("WARNING:"), "Skipped generating down script:", err)
+		return out, "", nil
+	}
+	return out, down, nil
+}
+
+// Computes the reverse diff by swapping source and target, then verifies it on the
+// shadow database by applying up, down, and up again.
+func diffRollback(ctx context.Context, up string, shadowConfig, config pgconn.Config, schema []string, w io.Writer, differ DiffFunc, options ...func(*pgx.ConnConfig)) (string, error) {
+	fmt.Fprintln(w, "Diffing rollback...")
+	down, err := differ(ctx, config, shadowConfig, schema, options...)
+	if err != nil {
+		return "", err
+	} else if len(down) < 2 {
+		return "", errors.New("reverse diff is empty")
+	}
+	upFile, err := migration.NewMigrationFromReader(strings.NewReader(up))
+	if err != nil {
+		return "", err
+	}
+	downFile, err := migration.NewMigrationFromReader(strings.NewReader(down))
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintln(w, "Verifying rollback on shadow database...")
+	conn, err := ConnectShadowDatabase(ctx, 10*time.Second, options...)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close(context.Background())
+	for _, m := range []*migration.MigrationFile{upFile, downFile, upFile} {
+		if err := m.ExecBatch(ctx, conn); err != nil {
+			return "", err
+		}
+	}
+	return down, nil
 }
 
 func migrateBaseDatabase(ctx context.Context, config pgconn.Config, migrations []string, fsys afero.Fs, options ...func(*pgx.ConnConfig)) error {
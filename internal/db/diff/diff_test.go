@@ -66,11 +66,23 @@ func TestRun(t *testing.T) {
 		apitest.MockDockerStart(utils.Docker, utils.GetRegistryImageUrl(utils.Config.EdgeRuntime.Image), "test-migra")
 		diff := "create table test();"
 		require.NoError(t, apitest.MockDockerLogs(utils.Docker, "test-migra", diff))
+		apitest.MockDockerStart(utils.Docker, utils.GetRegistryImageUrl(utils.Config.EdgeRuntime.Image), "test-migra-down")
+		down := "drop table test;"
+		require.NoError(t, apitest.MockDockerLogs(utils.Docker, "test-migra-down", down))
 		// Setup mock postgres
 		conn := pgtest.NewConn()
 		conn.Query(CREATE_TEMPLATE).
 			Reply("CREATE DATABASE")
 		defer conn.Close(t)
+		verify := pgtest.NewConn()
+		verify.Query("create table test()").
+			Reply("CREATE TABLE").
+			Query("drop table test").
+			Reply("DROP TABLE").
+			Query("create table test()").
+			Reply("CREATE TABLE")
+		defer verify.Close(t)
+		shadows := []*pgtest.MockConn{conn, verify}
 		// Run test
 		err := Run(context.Background(), []string{"public"}, "file", dbConfig, DiffSchemaMigra, fsys, func(cc *pgx.ConnConfig) {
 			if cc.Host == dbConfig.Host {
@@ -78,9 +90,15 @@ func TestRun(t *testing.T) {
 				cc.LookupFunc = func(ctx context.Context, host string) (addrs []string, err error) {
 					return nil, errors.New("server refused TLS connection")
 				}
+			} else if len(cc.Fallbacks) == 0 {
+				// Reverse diff checks root CA of shadow database with sslmode=require
+				cc.LookupFunc = func(ctx context.Context, host string) (addrs []string, err error) {
+					return nil, errors.New("server refused TLS connection")
+				}
 			} else {
-				// Hijack connection to shadow database
-				conn.Intercept(cc)
+				// Hijack connections to shadow database
+				shadows[0].Intercept(cc)
+				shadows = shadows[1:]
 			}
 		})
 		// Check error
@@ -89,11 +107,14 @@ func TestRun(t *testing.T) {
 		// Check diff file
 		files, err := afero.ReadDir(fsys, utils.MigrationsDir)
 		assert.NoError(t, err)
-		assert.Equal(t, 1, len(files))
-		diffPath := filepath.Join(utils.MigrationsDir, files[0].Name())
-		contents, err := afero.ReadFile(fsys, diffPath)
+		require.Equal(t, 2, len(files))
+		upPath := filepath.Join(utils.MigrationsDir, files[1].Name())
+		contents, err := afero.ReadFile(fsys, upPath)
 		assert.NoError(t, err)
 		assert.Equal(t, []byte(diff), contents)
+		contents, err = afero.ReadFile(fsys, migration.DownFilePath(upPath))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(down), contents)
 	})
 
 	t.Run("throws error on failure to diff target", func(t *testing.T) {
@@ -139,7 +160,7 @@ func TestMigrateShadow(t *testing.T) {
 			Reply("RESET").
 			Query(sql).
 			Reply("CREATE SCHEMA").
-			Query(migration.INSERT_MIGRATION_VERSION, "0", "test", []string{sql}).
+			Query(migration.INSERT_MIGRATION_VERSION, "0", "test", []string{sql}, helper.MigrationHash(sql)).
 			Reply("INSERT 0 1")
 		// Run test
 		err := MigrateShadowDatabase(context.Background(), "test-shadow-db", fsys, conn.Intercept)
@@ -318,7 +339,7 @@ create schema public`)
 			Reply("RESET").
 			Query(sql).
 			Reply("CREATE SCHEMA").
-			Query(migration.INSERT_MIGRATION_VERSION, "0", "test", []string{sql}).
+			Query(migration.INSERT_MIGRATION_VERSION, "0", "test", []string{sql}, helper.MigrationHash(sql)).
 			Reply("INSERT 0 1")
 		// Run test
 		diff, err := DiffDatabase(context.Background(), []string{"public"}, dbConfig, io.Discard, fsys, DiffSchemaMigra, func(cc *pgx.ConnConfig) {
@@ -340,7 +361,7 @@ create schema public`)
 }
 
 func TestDropStatements(t *testing.T) {
-	drops := findDropStatements("create table t(); drop table t; alter table t drop column c")
+	drops := FindDropStatements("create table t(); drop table t; alter table t drop column c")
 	assert.Equal(t, []string{"drop table t", "alter table t drop column c"}, drops)
 }
 
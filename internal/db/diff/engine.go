@@ -0,0 +1,142 @@
+package diff
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/go-errors/errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/supabase/cli/internal/utils"
+)
+
+// Differ generates SQL statements that migrate the source database schema to match target.
+type Differ interface {
+	Diff(ctx context.Context, source, target pgconn.Config, schema []string, options ...func(*pgx.ConnConfig)) (string, error)
+}
+
+func (f DiffFunc) Diff(ctx context.Context, source, target pgconn.Config, schema []string, options ...func(*pgx.ConnConfig)) (string, error) {
+	return f(ctx, source, target, schema, options...)
+}
+
+const (
+	EngineMigra    = "migra"
+	EnginePgAdmin  = "pgadmin"
+	EnginePgSchema = "pg-schema"
+	EnginePgDelta  = "pg-delta"
+
+	// External differs are specified as "exec:<command> [args...]" or "docker:<image>"
+	execPrefix   = "exec:"
+	dockerPrefix = "docker:"
+)
+
+var engines = map[string]Differ{
+	EngineMigra:    DiffFunc(DiffSchemaMigra),
+	EnginePgAdmin:  DiffFunc(DiffSchemaPgAdmin),
+	EnginePgSchema: DiffFunc(DiffPgSchema),
+	EnginePgDelta:  DiffFunc(DiffPgDelta),
+}
+
+// RegisterEngine adds a named differ to the registry, replacing any existing engine of the same name.
+func RegisterEngine(name string, differ Differ) {
+	engines[name] = differ
+}
+
+// EngineNames returns the sorted names of all registered engines.
+func EngineNames() []string {
+	names := make([]string, 0, len(engines))
+	for k := range engines {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// GetEngine resolves a registered engine by name, or an external differ by prefix. Defaults to
+// the engine configured in config.toml, then migra.
+func GetEngine(name string) (Differ, error) {
+	name = engineOrDefault(name)
+	if cmd, ok := strings.CutPrefix(name, execPrefix); ok {
+		if args := strings.Fields(cmd); len(args) > 0 {
+			return &execDiffer{args: args}, nil
+		}
+	} else if image, ok := strings.CutPrefix(name, dockerPrefix); ok {
+		if image = strings.TrimSpace(image); len(image) > 0 {
+			return &dockerDiffer{image: image}, nil
+		}
+	} else if differ, ok := engines[name]; ok {
+		return differ, nil
+	}
+	return nil, errors.Errorf("unknown diff engine %q: must be one of [ %s ], %s<command> or %s<image>", name, strings.Join(EngineNames(), " | "), execPrefix, dockerPrefix)
+}
+
+func engineOrDefault(name string) string {
+	if len(name) == 0 {
+		name = utils.Config.Db.Migrations.DiffEngine
+	}
+	if len(name) == 0 {
+		name = EngineMigra
+	}
+	return name
+}
+
+// Environment variables passed to external differs, matching the built-in scripts.
+func externalEnv(source, target pgconn.Config, schema []string) []string {
+	env := []string{
+		"SOURCE=" + utils.ToPostgresURL(source),
+		"TARGET=" + utils.ToPostgresURL(target),
+	}
+	if len(schema) > 0 {
+		env = append(env, "INCLUDED_SCHEMAS="+strings.Join(schema, ","))
+	} else {
+		env = append(env, "EXCLUDED_SCHEMAS="+strings.Join(managedSchemas, ","))
+	}
+	return env
+}
+
+type execDiffer struct {
+	args []string
+}
+
+func (d *execDiffer) Diff(ctx context.Context, source, target pgconn.Config, schema []string, _ ...func(*pgx.ConnConfig)) (string, error) {
+	cmd := exec.CommandContext(ctx, d.args[0], d.args[1:]...)
+	cmd.Env = append(os.Environ(), externalEnv(source, target, schema)...)
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", errors.Errorf("error diffing schema: %w:\n%s", err, stderr.String())
+	}
+	return out.String(), nil
+}
+
+type dockerDiffer struct {
+	image string
+}
+
+func (d *dockerDiffer) Diff(ctx context.Context, source, target pgconn.Config, schema []string, _ ...func(*pgx.ConnConfig)) (string, error) {
+	var out, stderr bytes.Buffer
+	if err := utils.DockerRunOnceWithConfig(
+		ctx,
+		container.Config{
+			Image: d.image,
+			Env:   externalEnv(source, target, schema),
+		},
+		container.HostConfig{
+			NetworkMode: network.NetworkHost,
+		},
+		network.NetworkingConfig{},
+		"",
+		&out,
+		&stderr,
+	); err != nil {
+		return "", errors.Errorf("error diffing schema: %w:\n%s", err, stderr.String())
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,125 @@
+package diff
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/cli/internal/utils"
+)
+
+func TestGetEngine(t *testing.T) {
+	t.Run("resolves registered engine", func(t *testing.T) {
+		differ, err := GetEngine(EnginePgDelta)
+		// Check error
+		assert.NoError(t, err)
+		assert.NotNil(t, differ)
+	})
+
+	t.Run("defaults to configured engine", func(t *testing.T) {
+		utils.Config.Db.Migrations.DiffEngine = "docker:acme/differ:1.0"
+		defer func() { utils.Config.Db.Migrations.DiffEngine = "" }()
+		// Run test
+		differ, err := GetEngine("")
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, &dockerDiffer{image: "acme/differ:1.0"}, differ)
+	})
+
+	t.Run("parses external command", func(t *testing.T) {
+		differ, err := GetEngine("exec:./bin/differ --json")
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, &execDiffer{args: []string{"./bin/differ", "--json"}}, differ)
+	})
+
+	t.Run("throws error on unknown engine", func(t *testing.T) {
+		_, err := GetEngine("exec: ")
+		// Check error
+		assert.ErrorContains(t, err, `unknown diff engine "exec: "`)
+		_, err = GetEngine("liquibase")
+		assert.ErrorContains(t, err, "migra | pg-delta | pg-schema | pgadmin")
+	})
+}
+
+func TestExecDiffer(t *testing.T) {
+	// Setup external differ
+	script := filepath.Join(t.TempDir(), "differ.sh")
+	require.NoError(t, os.WriteFile(script, []byte(`#!/bin/sh
+[ -n "$SOURCE" ] || exit 1
+echo "create schema $INCLUDED_SCHEMAS;"
+`), 0755))
+	differ, err := GetEngine("exec:" + script)
+	require.NoError(t, err)
+	// Run test
+	out, err := differ.Diff(context.Background(), dbConfig, dbConfig, []string{"private"})
+	// Check error
+	assert.NoError(t, err)
+	assert.Equal(t, "create schema private;\n", out)
+}
+
+// registerEngine adds a differ to the registry for the duration of a test.
+func registerEngine(t *testing.T, name string, differ Differ) {
+	prev, ok := engines[name]
+	RegisterEngine(name, differ)
+	t.Cleanup(func() {
+		if ok {
+			engines[name] = prev
+		} else {
+			delete(engines, name)
+		}
+	})
+}
+
+func TestCompareDiffer(t *testing.T) {
+	mockDiffer := func(out string, err error) DiffFunc {
+		return func(context.Context, pgconn.Config, pgconn.Config, []string, ...func(*pgx.ConnConfig)) (string, error) {
+			return out, err
+		}
+	}
+
+	t.Run("reports disagreeing statements", func(t *testing.T) {
+		registerEngine(t, "left", mockDiffer("create table a ();\n-- comment\nalter  table a\n  add column id int;\n", nil))
+		registerEngine(t, "right", mockDiffer("alter table a add column id int;\ncreate table b ();\n", nil))
+		var stderr bytes.Buffer
+		differ, err := CompareDiffer("left", "right", &stderr)
+		require.NoError(t, err)
+		// Run test
+		out, err := differ(context.Background(), dbConfig, dbConfig, nil)
+		// Check error
+		assert.NoError(t, err)
+		assert.Contains(t, out, "create table a ();")
+		assert.Contains(t, stderr.String(), "(1):\ncreate table a ();\n")
+		assert.Contains(t, stderr.String(), "(1):\ncreate table b ();\n")
+	})
+
+	t.Run("throws error on engine failure", func(t *testing.T) {
+		errTest := errors.New("network error")
+		registerEngine(t, "left", mockDiffer("create table a ();", nil))
+		registerEngine(t, "right", mockDiffer("", errTest))
+		var stderr bytes.Buffer
+		differ, err := CompareDiffer("left", "right", &stderr)
+		require.NoError(t, err)
+		// Run test
+		_, err = differ(context.Background(), dbConfig, dbConfig, nil)
+		// Check error
+		assert.ErrorIs(t, err, errTest)
+	})
+}
+
+func TestCompareStatements(t *testing.T) {
+	left, right := CompareStatements(
+		"create table a ();\ncreate table a ();\ngrant all on a to anon;\n",
+		"grant all\n  on a to anon;\ncreate table a ();\n",
+	)
+	// Check error
+	assert.Equal(t, []string{"create table a ()"}, left)
+	assert.Empty(t, right)
+}
@@ -0,0 +1,138 @@
+package drift
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/spf13/afero"
+	"github.com/supabase/cli/internal/db/diff"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/parser"
+)
+
+// Finding is a schema change on the target database that is not tracked by local migrations.
+type Finding struct {
+	Action    string `json:"action"`
+	Type      string `json:"type,omitempty"`
+	Object    string `json:"object,omitempty"`
+	Statement string `json:"statement"`
+}
+
+func Run(ctx context.Context, schema []string, config pgconn.Config, differ diff.DiffFunc, fsys afero.Fs, options ...func(*pgx.ConnConfig)) error {
+	// Statements transform the schema built from local migrations into the target schema
+	out, err := diff.DiffDatabase(ctx, schema, config, os.Stderr, fsys, differ, options...)
+	if err != nil {
+		return err
+	}
+	findings, err := ParseFindings(out)
+	if err != nil {
+		return err
+	}
+	if err := PrintReport(findings, utils.OutputFormat.Value, os.Stdout); err != nil {
+		return err
+	}
+	if len(findings) > 0 {
+		return errors.Errorf("found %d schema changes not tracked by local migrations", len(findings))
+	}
+	return nil
+}
+
+var (
+	statementPattern = regexp.MustCompile(`(?is)^(create|alter|drop|comment\s+on)\s+(?:or\s+replace\s+)?(?:unique\s+)?(?:materialized\s+)?(aggregate|domain|extension|function|index|policy|procedure|publication|role|schema|sequence|table|trigger|type|view)?\s*(?:concurrently\s+)?(?:if\s+(?:not\s+)?exists\s+)?("[^"]*"(?:\."[^"]*")*|[^\s(;]+)?`)
+	privilegePattern = regexp.MustCompile(`(?is)^(grant|revoke)\s+.*?\s+on\s+(?:(function|schema|sequence|table|type)\s+)?("[^"]*"(?:\."[^"]*")*|[^\s(;]+)`)
+)
+
+func ParseFindings(sql string) ([]Finding, error) {
+	lines, err := parser.SplitAndTrim(strings.NewReader(sql))
+	if err != nil {
+		return nil, err
+	}
+	result := []Finding{}
+	for _, line := range lines {
+		stat := stripComments(line)
+		if len(stat) == 0 || setPattern.MatchString(stat) {
+			// Session settings are not schema changes
+			continue
+		}
+		f := Finding{Statement: line}
+		if matches := privilegePattern.FindStringSubmatch(stat); len(matches) > 0 {
+			f.Action = strings.ToLower(matches[1])
+			f.Type = strings.ToLower(matches[2])
+			f.Object = matches[3]
+		} else if matches := statementPattern.FindStringSubmatch(stat); len(matches) > 0 {
+			f.Action = strings.ToLower(strings.Join(strings.Fields(matches[1]), " "))
+			f.Type = strings.ToLower(matches[2])
+			f.Object = matches[3]
+		} else {
+			f.Action = strings.ToLower(strings.Fields(stat)[0])
+		}
+		result = append(result, f)
+	}
+	return result, nil
+}
+
+var setPattern = regexp.MustCompile(`(?i)^(set|reset)\s+`)
+
+func stripComments(stat string) string {
+	var lines []string
+	for _, line := range strings.Split(stat, "\n") {
+		if trimmed := strings.TrimSpace(line); len(trimmed) > 0 && !strings.HasPrefix(trimmed, "--") {
+			lines = append(lines, trimmed)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PrintReport writes findings as a markdown summary by default, or encoded in the given output format.
+func PrintReport(findings []Finding, format string, w io.Writer) error {
+	switch format {
+	case utils.OutputPretty:
+		if _, err := io.WriteString(w, toMarkdown(findings)); err != nil {
+			return errors.Errorf("failed to print report markdown: %w", err)
+		}
+		return nil
+	case utils.OutputEnv:
+		return errors.New(utils.ErrEnvNotSupported)
+	}
+	return utils.EncodeOutput(format, w, findings)
+}
+
+func toMarkdown(findings []Finding) string {
+	var md strings.Builder
+	md.WriteString("## Schema drift\n\n")
+	if len(findings) == 0 {
+		md.WriteString("No schema drift found.\n")
+		return md.String()
+	}
+	fmt.Fprintf(&md, "Found %d schema changes not tracked by local migrations.\n\n", len(findings))
+	md.WriteString("|Action|Type|Object|\n|-|-|-|\n")
+	for _, f := range findings {
+		fmt.Fprintf(&md, "|`%s`|`%s`|`%s`|\n", f.Action, orBlank(f.Type), escapeCell(orBlank(f.Object)))
+	}
+	md.WriteString("\n```sql\n")
+	for _, f := range findings {
+		md.WriteString(f.Statement)
+		md.WriteString(";\n")
+	}
+	md.WriteString("```\n")
+	return md.String()
+}
+
+func orBlank(s string) string {
+	if len(s) == 0 {
+		return " "
+	}
+	return s
+}
+
+// Quoted identifiers may contain pipes, which would otherwise split the table cell.
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
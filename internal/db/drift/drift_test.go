@@ -0,0 +1,87 @@
+package drift
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/cli/internal/utils"
+)
+
+func TestParseFindings(t *testing.T) {
+	t.Run("parses diff statements", func(t *testing.T) {
+		sql := `set check_function_bodies = off;
+
+create table "public"."audit" ("id" bigint not null);
+
+-- manual index
+CREATE UNIQUE INDEX IF NOT EXISTS audit_pkey ON public.audit USING btree (id);
+
+drop policy "Enable read" on "public"."todos";
+
+alter table "public"."todos" add column "done" boolean;
+
+grant select on table "public"."audit" to "anon";
+`
+		// Run test
+		findings, err := ParseFindings(sql)
+		// Check error
+		require.NoError(t, err)
+		assert.Equal(t, []Finding{
+			{Action: "create", Type: "table", Object: `"public"."audit"`, Statement: `create table "public"."audit" ("id" bigint not null)`},
+			{Action: "create", Type: "index", Object: "audit_pkey", Statement: "-- manual index\nCREATE UNIQUE INDEX IF NOT EXISTS audit_pkey ON public.audit USING btree (id)"},
+			{Action: "drop", Type: "policy", Object: `"Enable read"`, Statement: `drop policy "Enable read" on "public"."todos"`},
+			{Action: "alter", Type: "table", Object: `"public"."todos"`, Statement: `alter table "public"."todos" add column "done" boolean`},
+			{Action: "grant", Type: "table", Object: `"public"."audit"`, Statement: `grant select on table "public"."audit" to "anon"`},
+		}, findings)
+	})
+
+	t.Run("returns empty on no changes", func(t *testing.T) {
+		// Run test
+		findings, err := ParseFindings("")
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, findings)
+	})
+}
+
+func TestPrintReport(t *testing.T) {
+	findings := []Finding{{Action: "create", Type: "table", Object: `"public"."audit"`, Statement: `create table "public"."audit" ()`}}
+
+	t.Run("prints markdown", func(t *testing.T) {
+		var out bytes.Buffer
+		// Run test
+		err := PrintReport(findings, utils.OutputPretty, &out)
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, "## Schema drift\n\nFound 1 schema changes not tracked by local migrations.\n\n"+
+			"|Action|Type|Object|\n|-|-|-|\n|`create`|`table`|`\"public\".\"audit\"`|\n\n"+
+			"```sql\ncreate table \"public\".\"audit\" ();\n```\n", out.String())
+	})
+
+	t.Run("prints empty json", func(t *testing.T) {
+		var out bytes.Buffer
+		// Run test
+		err := PrintReport([]Finding{}, utils.OutputJson, &out)
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, "[]\n", out.String())
+	})
+
+	t.Run("escapes pipe in object name", func(t *testing.T) {
+		var out bytes.Buffer
+		// Run test
+		err := PrintReport([]Finding{{Action: "drop", Type: "table", Object: `"a|b"`, Statement: `drop table "a|b"`}}, utils.OutputPretty, &out)
+		// Check error
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), "|`drop`|`table`|`\"a\\|b\"`|\n")
+	})
+
+	t.Run("throws error on env format", func(t *testing.T) {
+		// Run test
+		err := PrintReport(findings, utils.OutputEnv, &bytes.Buffer{})
+		// Check error
+		assert.ErrorIs(t, err, utils.ErrEnvNotSupported)
+	})
+}
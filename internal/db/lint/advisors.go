@@ -0,0 +1,101 @@
+package lint
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/go-errors/errors"
+	"github.com/jackc/pgx/v4"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/migration"
+)
+
+var (
+	//go:embed templates/rules/rls_disabled_in_public.sql
+	rlsDisabledScript string
+	//go:embed templates/rules/security_definer_view.sql
+	securityDefinerViewScript string
+	//go:embed templates/rules/function_search_path_mutable.sql
+	searchPathMutableScript string
+	//go:embed templates/rules/unindexed_foreign_keys.sql
+	unindexedForeignKeysScript string
+	//go:embed templates/rules/duplicate_index.sql
+	duplicateIndexScript string
+)
+
+// Rule is a local equivalent of a lint run by the hosted database advisors.
+type Rule struct {
+	Name  string
+	Level string
+	// Query takes an array of schema names and returns the entity, message, and hint of each finding.
+	Query string
+	// Exposed restricts the rule to schemas served by the Data API.
+	Exposed bool
+}
+
+var Rules = []Rule{
+	{Name: "rls_disabled_in_public", Level: AllowedLevels[1], Query: rlsDisabledScript, Exposed: true},
+	{Name: "security_definer_view", Level: AllowedLevels[1], Query: securityDefinerViewScript},
+	{Name: "function_search_path_mutable", Level: AllowedLevels[0], Query: searchPathMutableScript},
+	{Name: "unindexed_foreign_keys", Level: AllowedLevels[0], Query: unindexedForeignKeysScript},
+	{Name: "duplicate_index", Level: AllowedLevels[0], Query: duplicateIndexScript},
+}
+
+func LintAdvisors(ctx context.Context, conn *pgx.Conn, schema []string) ([]Result, error) {
+	if len(schema) == 0 {
+		var err error
+		if schema, err = migration.ListUserSchemas(ctx, conn); err != nil {
+			return nil, err
+		}
+	}
+	fmt.Fprintln(os.Stderr, "Running advisor rules...")
+	// Group issues by entity while preserving the order of rules
+	var result []Result
+	index := map[string]int{}
+	for _, r := range Rules {
+		target := schema
+		if r.Exposed {
+			if target = exposedSchemas(schema); len(target) == 0 {
+				continue
+			}
+		}
+		rows, err := conn.Query(ctx, r.Query, target)
+		if err != nil {
+			return nil, errors.Errorf("failed to query rows: %w", err)
+		}
+		for rows.Next() {
+			var entity, message, hint string
+			if err := rows.Scan(&entity, &message, &hint); err != nil {
+				return nil, errors.Errorf("failed to scan rows: %w", err)
+			}
+			i, ok := index[entity]
+			if !ok {
+				i = len(result)
+				index[entity] = i
+				result = append(result, Result{Object: entity})
+			}
+			result[i].Issues = append(result[i].Issues, Issue{
+				Level:   r.Level,
+				Message: message,
+				Hint:    hint,
+				Context: r.Name,
+			})
+		}
+		if err := rows.Err(); err != nil {
+			return nil, errors.Errorf("failed to parse rows: %w", err)
+		}
+	}
+	return result, nil
+}
+
+func exposedSchemas(schema []string) (result []string) {
+	for _, s := range schema {
+		if slices.Contains(utils.Config.Api.Schemas, s) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -39,7 +39,7 @@ func toEnum(level string) LintLevel {
 	return -1
 }
 
-func Run(ctx context.Context, schema []string, level string, failOn string, config pgconn.Config, fsys afero.Fs, options ...func(*pgx.ConnConfig)) error {
+func Run(ctx context.Context, schema []string, level string, failOn string, advisors bool, config pgconn.Config, fsys afero.Fs, options ...func(*pgx.ConnConfig)) error {
 	// Sanity checks.
 	conn, err := utils.ConnectByConfig(ctx, config, options...)
 	if err != nil {
@@ -51,6 +51,13 @@ func Run(ctx context.Context, schema []string, level string, failOn string, conf
 	if err != nil {
 		return err
 	}
+	if advisors {
+		findings, err := LintAdvisors(ctx, conn, schema)
+		if err != nil {
+			return err
+		}
+		result = append(result, findings...)
+	}
 	if len(result) == 0 {
 		fmt.Fprintln(os.Stderr, "\nNo schema errors found")
 		return nil
@@ -79,7 +86,7 @@ func Run(ctx context.Context, schema []string, level string, failOn string, conf
 
 func filterResult(result []Result, minLevel LintLevel) (filtered []Result) {
 	for _, r := range result {
-		out := Result{Function: r.Function}
+		out := Result{Function: r.Function, Object: r.Object}
 		for _, issue := range r.Issues {
 			if toEnum(issue.Level) >= minLevel {
 				out.Issues = append(out.Issues, issue)
@@ -184,6 +191,8 @@ type Issue struct {
 }
 
 type Result struct {
-	Function string  `json:"function"`
-	Issues   []Issue `json:"issues"`
+	Function string `json:"function,omitempty"`
+	// Object is the table, view, or function reported by advisor rules
+	Object string  `json:"object,omitempty"`
+	Issues []Issue `json:"issues"`
 }
@@ -65,7 +65,7 @@ func TestLintCommand(t *testing.T) {
 		Reply("SELECT 1", []any{"f1", string(data)}).
 		Query("rollback").Reply("ROLLBACK")
 	// Run test
-	err = Run(context.Background(), []string{"public"}, "warning", "none", dbConfig, fsys, conn.Intercept)
+	err = Run(context.Background(), []string{"public"}, "warning", "none", false, dbConfig, fsys, conn.Intercept)
 	// Check error
 	assert.NoError(t, err)
 	assert.Empty(t, apitest.ListUnmatchedRequests())
@@ -256,7 +256,7 @@ func TestPrintResult(t *testing.T) {
 			Reply("SELECT 1", []any{"f1", `{"function":"22751","issues":[{"level":"warning","message":"test warning"}]}`}).
 			Query("rollback").Reply("ROLLBACK")
 		// Run test
-		err := Run(context.Background(), []string{"public"}, "warning", "warning", dbConfig, fsys, conn.Intercept)
+		err := Run(context.Background(), []string{"public"}, "warning", "warning", false, dbConfig, fsys, conn.Intercept)
 		// Check error
 		assert.ErrorContains(t, err, "fail-on is set to warning, non-zero exit")
 	})
@@ -274,7 +274,7 @@ func TestPrintResult(t *testing.T) {
 			Reply("SELECT 1", []any{"f1", `{"function":"22751","issues":[{"level":"error","message":"test error"}]}`}).
 			Query("rollback").Reply("ROLLBACK")
 		// Run test
-		err := Run(context.Background(), []string{"public"}, "warning", "error", dbConfig, fsys, conn.Intercept)
+		err := Run(context.Background(), []string{"public"}, "warning", "error", false, dbConfig, fsys, conn.Intercept)
 		// Check error
 		assert.ErrorContains(t, err, "fail-on is set to error, non-zero exit")
 	})
@@ -292,8 +292,101 @@ func TestPrintResult(t *testing.T) {
 			Reply("SELECT 1", []any{"f1", `{"function":"22751","issues":[{"level":"error","message":"test error"}]}`}).
 			Query("rollback").Reply("ROLLBACK")
 		// Run test
-		err := Run(context.Background(), []string{"public"}, "warning", "none", dbConfig, fsys, conn.Intercept)
+		err := Run(context.Background(), []string{"public"}, "warning", "none", false, dbConfig, fsys, conn.Intercept)
 		// Check error
 		assert.NoError(t, err)
 	})
 }
+
+func TestLintAdvisors(t *testing.T) {
+	utils.Config.Api.Schemas = []string{"public"}
+
+	t.Run("groups findings by entity", func(t *testing.T) {
+		schema := []string{"public"}
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(rlsDisabledScript, schema).
+			Reply("SELECT 1", []any{"public.todos", "Table public.todos is exposed", "ALTER TABLE public.todos ENABLE ROW LEVEL SECURITY;"}).
+			Query(securityDefinerViewScript, schema).
+			Reply("SELECT 0").
+			Query(searchPathMutableScript, schema).
+			Reply("SELECT 1", []any{"public.f1()", "Function public.f1 has a role mutable search_path", "ALTER FUNCTION public.f1() SET search_path = '';"}).
+			Query(unindexedForeignKeysScript, schema).
+			Reply("SELECT 1", []any{"public.todos", "Foreign key todos_user_id_fkey is not indexed", "CREATE INDEX ON public.todos (user_id);"}).
+			Query(duplicateIndexScript, schema).
+			Reply("SELECT 0")
+		// Connect to mock
+		ctx := context.Background()
+		mock, err := utils.ConnectByConfig(ctx, dbConfig, conn.Intercept)
+		require.NoError(t, err)
+		defer mock.Close(ctx)
+		// Run test
+		result, err := LintAdvisors(ctx, mock, schema)
+		assert.NoError(t, err)
+		// Validate result
+		assert.Equal(t, []Result{{
+			Object: "public.todos",
+			Issues: []Issue{{
+				Level:   AllowedLevels[1],
+				Message: "Table public.todos is exposed",
+				Hint:    "ALTER TABLE public.todos ENABLE ROW LEVEL SECURITY;",
+				Context: "rls_disabled_in_public",
+			}, {
+				Level:   AllowedLevels[0],
+				Message: "Foreign key todos_user_id_fkey is not indexed",
+				Hint:    "CREATE INDEX ON public.todos (user_id);",
+				Context: "unindexed_foreign_keys",
+			}},
+		}, {
+			Object: "public.f1()",
+			Issues: []Issue{{
+				Level:   AllowedLevels[0],
+				Message: "Function public.f1 has a role mutable search_path",
+				Hint:    "ALTER FUNCTION public.f1() SET search_path = '';",
+				Context: "function_search_path_mutable",
+			}},
+		}}, result)
+	})
+
+	t.Run("skips exposed rules on private schemas", func(t *testing.T) {
+		schema := []string{"private"}
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(securityDefinerViewScript, schema).
+			Reply("SELECT 0").
+			Query(searchPathMutableScript, schema).
+			Reply("SELECT 0").
+			Query(unindexedForeignKeysScript, schema).
+			Reply("SELECT 0").
+			Query(duplicateIndexScript, schema).
+			Reply("SELECT 0")
+		// Connect to mock
+		ctx := context.Background()
+		mock, err := utils.ConnectByConfig(ctx, dbConfig, conn.Intercept)
+		require.NoError(t, err)
+		defer mock.Close(ctx)
+		// Run test
+		result, err := LintAdvisors(ctx, mock, schema)
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("throws error on failure to query", func(t *testing.T) {
+		schema := []string{"public"}
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(rlsDisabledScript, schema).
+			ReplyError(pgerrcode.UndefinedFunction, "function has_table_privilege does not exist")
+		// Connect to mock
+		ctx := context.Background()
+		mock, err := utils.ConnectByConfig(ctx, dbConfig, conn.Intercept)
+		require.NoError(t, err)
+		defer mock.Close(ctx)
+		// Run test
+		_, err = LintAdvisors(ctx, mock, schema)
+		assert.ErrorContains(t, err, "function has_table_privilege does not exist")
+	})
+}
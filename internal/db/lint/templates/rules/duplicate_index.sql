@@ -0,0 +1,13 @@
+-- Ref: https://supabase.com/docs/guides/database/database-linter?lint=0009_duplicate_index
+SELECT format('%I.%I', n.nspname, c.relname),
+  format('Table %I.%I has identical indexes %s', n.nspname, c.relname, string_agg(quote_ident(ci.relname), ', ' ORDER BY ci.relname)),
+  'Drop all except one of the identical indexes.'
+FROM pg_catalog.pg_index i
+JOIN pg_catalog.pg_class ci ON ci.oid = i.indexrelid
+JOIN pg_catalog.pg_class c ON c.oid = i.indrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = ANY($1::text[])
+GROUP BY n.nspname, c.relname, i.indrelid, i.indkey::text, i.indclass::text, i.indcollation::text,
+  coalesce(pg_get_expr(i.indexprs, i.indrelid), ''), coalesce(pg_get_expr(i.indpred, i.indrelid), '')
+HAVING count(*) > 1
+ORDER BY 1;
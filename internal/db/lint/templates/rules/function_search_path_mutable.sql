@@ -0,0 +1,14 @@
+-- Ref: https://supabase.com/docs/guides/database/database-linter?lint=0011_function_search_path_mutable
+SELECT format('%I.%I(%s)', n.nspname, p.proname, pg_get_function_identity_arguments(p.oid)),
+  format('Function %I.%I has a role mutable search_path', n.nspname, p.proname),
+  format('ALTER FUNCTION %I.%I(%s) SET search_path = '''';', n.nspname, p.proname, pg_get_function_identity_arguments(p.oid))
+FROM pg_catalog.pg_proc p
+JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace
+WHERE p.prokind IN ('f', 'p') AND n.nspname = ANY($1::text[])
+AND NOT EXISTS (
+  SELECT 1 FROM unnest(coalesce(p.proconfig, '{}')) c WHERE c LIKE 'search_path=%'
+)
+AND NOT EXISTS (
+  SELECT 1 FROM pg_catalog.pg_depend d WHERE d.objid = p.oid AND d.deptype = 'e'
+)
+ORDER BY 1;
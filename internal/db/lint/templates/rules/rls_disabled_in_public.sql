@@ -0,0 +1,16 @@
+-- Ref: https://supabase.com/docs/guides/database/database-linter?lint=0013_rls_disabled_in_public
+SELECT format('%I.%I', n.nspname, c.relname),
+  format('Table %I.%I is exposed to the Data API without row level security enabled', n.nspname, c.relname),
+  format('ALTER TABLE %I.%I ENABLE ROW LEVEL SECURITY;', n.nspname, c.relname)
+FROM pg_catalog.pg_class c
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind IN ('r', 'p') AND NOT c.relrowsecurity AND n.nspname = ANY($1::text[])
+AND EXISTS (
+  SELECT 1 FROM pg_catalog.pg_roles r
+  WHERE r.rolname IN ('anon', 'authenticated')
+  AND has_table_privilege(r.oid, c.oid, 'SELECT, INSERT, UPDATE, DELETE')
+)
+AND NOT EXISTS (
+  SELECT 1 FROM pg_catalog.pg_depend d WHERE d.objid = c.oid AND d.deptype = 'e'
+)
+ORDER BY 1;
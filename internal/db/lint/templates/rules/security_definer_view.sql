@@ -0,0 +1,15 @@
+-- Ref: https://supabase.com/docs/guides/database/database-linter?lint=0010_security_definer_view
+SELECT format('%I.%I', n.nspname, c.relname),
+  format('View %I.%I bypasses row level security of the querying user', n.nspname, c.relname),
+  format('ALTER VIEW %I.%I SET (security_invoker = on);', n.nspname, c.relname)
+FROM pg_catalog.pg_class c
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind = 'v' AND n.nspname = ANY($1::text[])
+AND NOT EXISTS (
+  SELECT 1 FROM unnest(coalesce(c.reloptions, '{}')) o
+  WHERE lower(o) ~ '^security_invoker=(true|on|yes|1)$'
+)
+AND NOT EXISTS (
+  SELECT 1 FROM pg_catalog.pg_depend d WHERE d.objid = c.oid AND d.deptype = 'e'
+)
+ORDER BY 1;
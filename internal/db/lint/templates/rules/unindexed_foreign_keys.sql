@@ -0,0 +1,21 @@
+-- Ref: https://supabase.com/docs/guides/database/database-linter?lint=0001_unindexed_foreign_keys
+SELECT format('%I.%I', n.nspname, c.relname),
+  format('Foreign key %I on table %I.%I does not have a covering index', con.conname, n.nspname, c.relname),
+  format('CREATE INDEX ON %I.%I (%s);', n.nspname, c.relname, (
+    SELECT string_agg(quote_ident(a.attname), ', ' ORDER BY k.ord)
+    FROM unnest(con.conkey) WITH ORDINALITY k(attnum, ord)
+    JOIN pg_catalog.pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+  ))
+FROM pg_catalog.pg_constraint con
+JOIN pg_catalog.pg_class c ON c.oid = con.conrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE con.contype = 'f' AND n.nspname = ANY($1::text[])
+AND NOT EXISTS (
+  SELECT 1 FROM pg_catalog.pg_index i
+  WHERE i.indrelid = con.conrelid
+  AND (string_to_array(i.indkey::text, ' ')::smallint[])[1:array_length(con.conkey, 1)] @> con.conkey
+)
+AND NOT EXISTS (
+  SELECT 1 FROM pg_catalog.pg_depend d WHERE d.objid = c.oid AND d.deptype = 'e'
+)
+ORDER BY 1, 2;
@@ -3,13 +3,17 @@ package push
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/go-errors/errors"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	"github.com/spf13/afero"
+	"github.com/supabase/cli/internal/backups/restore_point/create"
+	"github.com/supabase/cli/internal/migration/list"
 	"github.com/supabase/cli/internal/migration/up"
 	"github.com/supabase/cli/internal/utils"
 	"github.com/supabase/cli/internal/utils/flags"
@@ -17,7 +21,7 @@ import (
 	"github.com/supabase/cli/pkg/vault"
 )
 
-func Run(ctx context.Context, dryRun, ignoreVersionMismatch bool, includeRoles, includeSeed bool, config pgconn.Config, fsys afero.Fs, options ...func(*pgx.ConnConfig)) error {
+func Run(ctx context.Context, dryRun, ignoreVersionMismatch, strict bool, includeRoles, includeSeed bool, restorePoint string, config pgconn.Config, fsys afero.Fs, options ...func(*pgx.ConnConfig)) error {
 	if dryRun {
 		fmt.Fprintln(os.Stderr, "DRY RUN: migrations will *not* be pushed to the database.")
 	}
@@ -31,6 +35,8 @@ func Run(ctx context.Context, dryRun, ignoreVersionMismatch bool, includeRoles,
 		fmt.Fprintln(os.Stderr, "Skipping migrations because it is disabled in config.toml for project:", flags.ProjectRef)
 	} else if pending, err = up.GetPendingMigrations(ctx, ignoreVersionMismatch, conn, fsys); err != nil {
 		return err
+	} else if err := checkDrift(ctx, strict, conn, fsys); err != nil {
+		return err
 	}
 	var seeds []migration.SeedFile
 	if includeSeed {
@@ -67,6 +73,7 @@ func Run(ctx context.Context, dryRun, ignoreVersionMismatch bool, includeRoles,
 			fmt.Fprint(os.Stderr, confirmSeedAll(seeds))
 		}
 	} else {
+		// Confirm everything up front so that the restore point precedes all writes
 		if len(globals) > 0 {
 			msg := "Do you want to create custom roles in the database cluster?"
 			if shouldPush, err := utils.NewConsole().PromptYesNo(ctx, msg, true); err != nil {
@@ -74,33 +81,39 @@ func Run(ctx context.Context, dryRun, ignoreVersionMismatch bool, includeRoles,
 			} else if !shouldPush {
 				return errors.New(context.Canceled)
 			}
-			if err := migration.SeedGlobals(ctx, globals, conn, afero.NewIOFS(fsys)); err != nil {
+		}
+		if len(pending) > 0 {
+			if err := confirmPush(ctx, pending); err != nil {
 				return err
 			}
 		}
-		if len(pending) > 0 {
-			msg := fmt.Sprintf("Do you want to push these migrations to the remote database?\n%s\n", confirmPushAll(pending))
+		if len(seeds) > 0 {
+			msg := fmt.Sprintf("Do you want to seed the remote database with these files?\n%s\n", confirmSeedAll(seeds))
 			if shouldPush, err := utils.NewConsole().PromptYesNo(ctx, msg, true); err != nil {
 				return err
 			} else if !shouldPush {
 				return errors.New(context.Canceled)
 			}
+		}
+		if err := createRestorePoint(ctx, restorePoint, flags.ProjectRef); err != nil {
+			return err
+		}
+		if len(globals) > 0 {
+			if err := migration.SeedGlobals(ctx, globals, conn, afero.NewIOFS(fsys)); err != nil {
+				return err
+			}
+		}
+		if len(pending) > 0 {
 			if err := vault.UpsertVaultSecrets(ctx, utils.Config.Db.Vault, conn); err != nil {
 				return err
 			}
-			if err := migration.ApplyMigrations(ctx, pending, conn, afero.NewIOFS(fsys)); err != nil {
+			if err := migration.ApplyMigrations(ctx, pending, conn, afero.NewIOFS(fsys), applyOptions(ctx)...); err != nil {
 				return err
 			}
 		} else {
 			fmt.Fprintln(os.Stderr, "Schema migrations are up to date.")
 		}
 		if len(seeds) > 0 {
-			msg := fmt.Sprintf("Do you want to seed the remote database with these files?\n%s\n", confirmSeedAll(seeds))
-			if shouldPush, err := utils.NewConsole().PromptYesNo(ctx, msg, true); err != nil {
-				return err
-			} else if !shouldPush {
-				return errors.New(context.Canceled)
-			}
 			if err := migration.SeedData(ctx, seeds, conn, afero.NewIOFS(fsys)); err != nil {
 				return err
 			}
@@ -112,6 +125,76 @@ func Run(ctx context.Context, dryRun, ignoreVersionMismatch bool, includeRoles,
 	return nil
 }
 
+func checkDrift(ctx context.Context, strict bool, conn *pgx.Conn, fsys afero.Fs) error {
+	hashes, err := migration.ReadRemoteHashes(ctx, conn)
+	if err != nil {
+		return err
+	}
+	remoteVersions := slices.Sorted(maps.Keys(hashes))
+	if drift, err := list.CheckDrift(remoteVersions, hashes, fsys); err != nil {
+		return err
+	} else if len(drift) > 0 && strict {
+		utils.CmdSuggestion = fmt.Sprintf("Run %s to update the remote history, or restore the local migration files.", utils.Aqua("supabase migration repair"))
+		return errors.New(migration.ErrDrift)
+	}
+	return nil
+}
+
+func RunViaApi(ctx context.Context, dryRun, ignoreVersionMismatch bool, restorePoint, projectRef string, fsys afero.Fs) error {
+	if dryRun {
+		fmt.Fprintln(os.Stderr, "DRY RUN: migrations will *not* be pushed to the database.")
+	}
+	history := migration.NewApiHistory(projectRef, *utils.GetSupabase())
+	var pending []string
+	var err error
+	if !utils.Config.Db.Migrations.Enabled {
+		fmt.Fprintln(os.Stderr, "Skipping migrations because it is disabled in config.toml for project:", projectRef)
+	} else if pending, err = up.ListPendingMigrations(ctx, ignoreVersionMismatch, history, fsys); err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		fmt.Println("Remote database is up to date.")
+		return nil
+	}
+	if dryRun {
+		fmt.Fprintln(os.Stderr, "Would push these migrations:")
+		fmt.Fprint(os.Stderr, confirmPushAll(pending))
+	} else {
+		if err := confirmPush(ctx, pending); err != nil {
+			return err
+		}
+		if err := createRestorePoint(ctx, restorePoint, projectRef); err != nil {
+			return err
+		}
+		if len(utils.Config.Db.Vault) > 0 {
+			fmt.Fprintln(os.Stderr, "Skipping vault secrets because they require a direct database connection.")
+		}
+		if err := history.ApplyMigrations(ctx, pending, afero.NewIOFS(fsys)); err != nil {
+			return err
+		}
+	}
+	fmt.Println("Finished " + utils.Aqua("supabase db push") + ".")
+	return nil
+}
+
+func confirmPush(ctx context.Context, pending []string) error {
+	msg := fmt.Sprintf("Do you want to push these migrations to the remote database?\n%s\n", confirmPushAll(pending))
+	if shouldPush, err := utils.NewConsole().PromptYesNo(ctx, msg, true); err != nil {
+		return err
+	} else if !shouldPush {
+		return errors.New(context.Canceled)
+	}
+	return nil
+}
+
+func createRestorePoint(ctx context.Context, restorePoint, projectRef string) error {
+	if len(restorePoint) == 0 {
+		return nil
+	}
+	_, err := create.CreateRestorePoint(ctx, projectRef, restorePoint)
+	return err
+}
+
 func confirmPushAll(pending []string) (msg string) {
 	for _, path := range pending {
 		filename := filepath.Base(path)
@@ -130,3 +213,17 @@ func confirmSeedAll(pending []migration.SeedFile) (msg string) {
 	}
 	return msg
 }
+
+func applyOptions(ctx context.Context) []migration.ApplyOptionFunc {
+	var opts []migration.ApplyOptionFunc
+	if timeout := utils.Config.Db.Migrations.LockTimeout; len(timeout) > 0 {
+		opts = append(opts,
+			migration.WithLockTimeout(timeout),
+			migration.WithLockRetry(utils.NewBackoffPolicy(ctx), utils.NewErrorCallback()),
+		)
+	}
+	if timeout := utils.Config.Db.Migrations.StatementTimeout; len(timeout) > 0 {
+		opts = append(opts, migration.WithStatementTimeout(timeout))
+	}
+	return opts
+}
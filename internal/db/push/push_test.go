@@ -4,18 +4,23 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/h2non/gock"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgerrcode"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/supabase/cli/internal/testing/apitest"
 	"github.com/supabase/cli/internal/testing/fstest"
 	"github.com/supabase/cli/internal/testing/helper"
 	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/flags"
+	"github.com/supabase/cli/pkg/api"
 	"github.com/supabase/cli/pkg/migration"
 	"github.com/supabase/cli/pkg/pgtest"
 )
@@ -38,9 +43,11 @@ func TestMigrationPush(t *testing.T) {
 		conn := pgtest.NewConn()
 		defer conn.Close(t)
 		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 0").
+			Query(migration.SELECT_MIGRATION_HASH).
 			Reply("SELECT 0")
 		// Run test
-		err := Run(context.Background(), true, false, true, true, dbConfig, fsys, conn.Intercept)
+		err := Run(context.Background(), true, false, false, true, true, "", dbConfig, fsys, conn.Intercept)
 		// Check error
 		assert.NoError(t, err)
 	})
@@ -52,18 +59,38 @@ func TestMigrationPush(t *testing.T) {
 		conn := pgtest.NewConn()
 		defer conn.Close(t)
 		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 0").
+			Query(migration.SELECT_MIGRATION_HASH).
 			Reply("SELECT 0")
 		// Run test
-		err := Run(context.Background(), false, false, false, false, dbConfig, fsys, conn.Intercept)
+		err := Run(context.Background(), false, false, false, false, false, "", dbConfig, fsys, conn.Intercept)
 		// Check error
 		assert.NoError(t, err)
 	})
 
+	t.Run("throws error on drift in strict mode", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		path := filepath.Join(utils.MigrationsDir, "0_test.sql")
+		require.NoError(t, afero.WriteFile(fsys, path, []byte("select 2"), 0644))
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 1", []any{"0"}).
+			Query(migration.SELECT_MIGRATION_HASH).
+			Reply("SELECT 1", []any{"0", helper.MigrationHash("select 1")})
+		// Run test
+		err := Run(context.Background(), false, false, true, false, false, "", dbConfig, fsys, conn.Intercept)
+		// Check error
+		assert.ErrorIs(t, err, migration.ErrDrift)
+	})
+
 	t.Run("throws error on connect failure", func(t *testing.T) {
 		// Setup in-memory fs
 		fsys := afero.NewMemMapFs()
 		// Run test
-		err := Run(context.Background(), false, false, false, false, pgconn.Config{}, fsys)
+		err := Run(context.Background(), false, false, false, false, false, "", pgconn.Config{}, fsys)
 		// Check error
 		assert.ErrorContains(t, err, "invalid port (outside range)")
 	})
@@ -77,7 +104,7 @@ func TestMigrationPush(t *testing.T) {
 		conn.Query(migration.LIST_MIGRATION_VERSION).
 			ReplyError(pgerrcode.InvalidCatalogName, `database "target" does not exist`)
 		// Run test
-		err := Run(context.Background(), false, false, false, false, pgconn.Config{
+		err := Run(context.Background(), false, false, false, false, false, "", pgconn.Config{
 			Host:     "db.supabase.co",
 			Port:     5432,
 			User:     "admin",
@@ -97,20 +124,105 @@ func TestMigrationPush(t *testing.T) {
 		conn := pgtest.NewConn()
 		defer conn.Close(t)
 		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 0").
+			Query(migration.SELECT_MIGRATION_HASH).
 			Reply("SELECT 0")
 		helper.MockMigrationHistory(conn).
 			Query("RESET ALL").
 			Reply("RESET").
-			Query(migration.INSERT_MIGRATION_VERSION, "0", "test", nil).
+			Query(migration.INSERT_MIGRATION_VERSION, "0", "test", nil, helper.MigrationHash()).
 			ReplyError(pgerrcode.NotNullViolation, `null value in column "version" of relation "schema_migrations"`)
 		// Run test
-		err := Run(context.Background(), false, false, false, false, dbConfig, fsys, conn.Intercept)
+		err := Run(context.Background(), false, false, false, false, false, "", dbConfig, fsys, conn.Intercept)
 		// Check error
 		assert.ErrorContains(t, err, `ERROR: null value in column "version" of relation "schema_migrations" (SQLSTATE 23502)`)
 		assert.ErrorContains(t, err, "At statement: 0\n"+migration.INSERT_MIGRATION_VERSION)
 	})
 }
 
+func TestPushWithRestorePoint(t *testing.T) {
+	flags.ProjectRef = apitest.RandomProjectRef()
+	token := apitest.RandomAccessToken(t)
+	t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+
+	t.Run("creates restore point before push", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		path := filepath.Join(utils.MigrationsDir, "0_test.sql")
+		require.NoError(t, afero.WriteFile(fsys, path, []byte{}, 0644))
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/" + flags.ProjectRef + "/database/backups/restore-point").
+			Reply(http.StatusCreated).
+			JSON(api.V1RestorePointResponse{Name: "before-push", Status: api.V1RestorePointResponseStatusAVAILABLE})
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 0").
+			Query(migration.SELECT_MIGRATION_HASH).
+			Reply("SELECT 0")
+		helper.MockMigrationHistory(conn).
+			Query("RESET ALL").
+			Reply("RESET").
+			Query(migration.INSERT_MIGRATION_VERSION, "0", "test", nil, helper.MigrationHash()).
+			Reply("INSERT 0 1")
+		// Run test
+		err := Run(context.Background(), false, false, false, false, false, "before-push", dbConfig, fsys, conn.Intercept)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on restore point failure", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		path := filepath.Join(utils.MigrationsDir, "0_test.sql")
+		require.NoError(t, afero.WriteFile(fsys, path, []byte{}, 0644))
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/" + flags.ProjectRef + "/database/backups/restore-point").
+			Reply(http.StatusServiceUnavailable)
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 0").
+			Query(migration.SELECT_MIGRATION_HASH).
+			Reply("SELECT 0")
+		// Run test
+		err := Run(context.Background(), false, false, false, false, false, "before-push", dbConfig, fsys, conn.Intercept)
+		// Check error
+		assert.ErrorContains(t, err, "unexpected create restore point status 503:")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("creates restore point before custom roles", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fsys, utils.CustomRolesPath, []byte("create role test"), 0644))
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/" + flags.ProjectRef + "/database/backups/restore-point").
+			Reply(http.StatusServiceUnavailable)
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 0").
+			Query(migration.SELECT_MIGRATION_HASH).
+			Reply("SELECT 0")
+		// Run test
+		err := Run(context.Background(), false, false, false, true, false, "before-push", dbConfig, fsys, conn.Intercept)
+		// Check error
+		assert.ErrorContains(t, err, "unexpected create restore point status 503:")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+}
+
 func TestPushAll(t *testing.T) {
 	t.Run("ignores missing roles and seed", func(t *testing.T) {
 		// Setup in-memory fs
@@ -121,14 +233,16 @@ func TestPushAll(t *testing.T) {
 		conn := pgtest.NewConn()
 		defer conn.Close(t)
 		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 0").
+			Query(migration.SELECT_MIGRATION_HASH).
 			Reply("SELECT 0")
 		helper.MockMigrationHistory(conn).
 			Query("RESET ALL").
 			Reply("RESET").
-			Query(migration.INSERT_MIGRATION_VERSION, "0", "test", nil).
+			Query(migration.INSERT_MIGRATION_VERSION, "0", "test", nil, helper.MigrationHash()).
 			Reply("INSERT 0 1")
 		// Run test
-		err := Run(context.Background(), false, false, true, true, dbConfig, fsys, conn.Intercept)
+		err := Run(context.Background(), false, false, false, true, true, "", dbConfig, fsys, conn.Intercept)
 		// Check error
 		assert.NoError(t, err)
 	})
@@ -143,9 +257,11 @@ func TestPushAll(t *testing.T) {
 		conn := pgtest.NewConn()
 		defer conn.Close(t)
 		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 0").
+			Query(migration.SELECT_MIGRATION_HASH).
 			Reply("SELECT 0")
 		// Run test
-		err := Run(context.Background(), false, false, true, true, dbConfig, fsys, conn.Intercept)
+		err := Run(context.Background(), false, false, false, true, true, "", dbConfig, fsys, conn.Intercept)
 		// Check error
 		assert.ErrorIs(t, err, context.Canceled)
 	})
@@ -159,9 +275,11 @@ func TestPushAll(t *testing.T) {
 		conn := pgtest.NewConn()
 		defer conn.Close(t)
 		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 0").
+			Query(migration.SELECT_MIGRATION_HASH).
 			Reply("SELECT 0")
 		// Run test
-		err := Run(context.Background(), false, false, true, false, dbConfig, fsys, conn.Intercept)
+		err := Run(context.Background(), false, false, false, true, false, "", dbConfig, fsys, conn.Intercept)
 		// Check error
 		assert.ErrorIs(t, err, os.ErrPermission)
 	})
@@ -179,19 +297,21 @@ func TestPushAll(t *testing.T) {
 		conn := pgtest.NewConn()
 		defer conn.Close(t)
 		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 0").
+			Query(migration.SELECT_MIGRATION_HASH).
 			Reply("SELECT 0").
 			Query(migration.SELECT_SEED_TABLE).
 			Reply("SELECT 0")
 		helper.MockMigrationHistory(conn).
 			Query("RESET ALL").
 			Reply("RESET").
-			Query(migration.INSERT_MIGRATION_VERSION, "0", "test", nil).
+			Query(migration.INSERT_MIGRATION_VERSION, "0", "test", nil, helper.MigrationHash()).
 			Reply("INSERT 0 1")
 		helper.MockSeedHistory(conn).
 			Query(migration.UPSERT_SEED_FILE, seedPath, digest).
 			ReplyError(pgerrcode.NotNullViolation, `null value in column "hash" of relation "seed_files"`)
 		// Run test
-		err := Run(context.Background(), false, false, false, true, dbConfig, fsys, conn.Intercept)
+		err := Run(context.Background(), false, false, false, false, true, "", dbConfig, fsys, conn.Intercept)
 		// Check error
 		assert.ErrorContains(t, err, `ERROR: null value in column "hash" of relation "seed_files" (SQLSTATE 23502)`)
 	})
@@ -0,0 +1,62 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/spf13/afero"
+	"github.com/supabase/cli/internal/db/diff"
+	"github.com/supabase/cli/internal/db/schema/plan"
+	"github.com/supabase/cli/internal/migration/new"
+	"github.com/supabase/cli/internal/migration/up"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/migration"
+)
+
+var errPendingMigrations = errors.New("Found local migrations not yet applied to the database.")
+
+func Run(ctx context.Context, schema []string, name string, config pgconn.Config, differ diff.DiffFunc, fsys afero.Fs, options ...func(*pgx.ConnConfig)) error {
+	conn, err := utils.ConnectByConfig(ctx, config, options...)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+	// Planning against an outdated database would generate a conflicting migration
+	if pending, err := up.GetPendingMigrations(ctx, false, conn, fsys); err != nil {
+		return err
+	} else if len(pending) > 0 {
+		utils.CmdSuggestion = fmt.Sprintf("Run %s to apply these migrations first:\n%s", utils.Aqua("supabase db push"), utils.Bold(strings.Join(pending, "\n")))
+		return errors.New(errPendingMigrations)
+	}
+	out, err := diff.DiffDeclared(ctx, schema, config, os.Stderr, fsys, differ, options...)
+	if err != nil {
+		return err
+	}
+	if len(out) < 2 {
+		fmt.Fprintln(os.Stderr, "Database schema is up to date with declared schema files.")
+		return nil
+	}
+	fmt.Fprintln(os.Stderr, "Planned changes:")
+	fmt.Fprintln(os.Stderr, out)
+	plan.WarnDropStatements(out)
+	if shouldApply, err := utils.NewConsole().PromptYesNo(ctx, "Do you want to apply this plan to the database?", false); err != nil {
+		return err
+	} else if !shouldApply {
+		return errors.New(context.Canceled)
+	}
+	path := new.GetMigrationPath(utils.GetCurrentTimestamp(), name)
+	if err := utils.WriteFile(path, []byte(out), fsys); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "Created new migration at "+utils.Bold(path))
+	if err := migration.ApplyMigrations(ctx, []string{path}, conn, afero.NewIOFS(fsys)); err != nil {
+		utils.CmdSuggestion = fmt.Sprintf("Fix the migration at %s and run %s to retry.", utils.Bold(path), utils.Aqua("supabase db push"))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package apply
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/cli/internal/db/diff"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/migration"
+	"github.com/supabase/cli/pkg/pgtest"
+)
+
+var dbConfig = pgconn.Config{
+	Host:     "127.0.0.1",
+	Port:     5432,
+	User:     "admin",
+	Password: "password",
+	Database: "postgres",
+}
+
+func TestSchemaApply(t *testing.T) {
+	t.Run("throws error on pending migrations", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		path := filepath.Join(utils.MigrationsDir, "20220727064247_test.sql")
+		require.NoError(t, afero.WriteFile(fsys, path, []byte("create table t ()"), 0644))
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 0")
+		// Run test
+		err := Run(context.Background(), nil, "test", dbConfig, diff.DiffSchemaMigra, fsys, conn.Intercept)
+		// Check error
+		assert.ErrorIs(t, err, errPendingMigrations)
+		assert.Contains(t, utils.CmdSuggestion, path)
+	})
+
+	t.Run("throws error on missing schema paths", func(t *testing.T) {
+		utils.Config.Db.Migrations.SchemaPaths = nil
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 0")
+		// Run test
+		err := Run(context.Background(), nil, "test", dbConfig, diff.DiffSchemaMigra, fsys, conn.Intercept)
+		// Check error
+		assert.ErrorIs(t, err, diff.ErrNoDeclaredSchema)
+	})
+}
@@ -0,0 +1,35 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/spf13/afero"
+	"github.com/supabase/cli/internal/db/diff"
+	"github.com/supabase/cli/internal/utils"
+)
+
+func Run(ctx context.Context, schema []string, config pgconn.Config, differ diff.DiffFunc, fsys afero.Fs, options ...func(*pgx.ConnConfig)) error {
+	out, err := diff.DiffDeclared(ctx, schema, config, os.Stderr, fsys, differ, options...)
+	if err != nil {
+		return err
+	}
+	if len(out) < 2 {
+		fmt.Fprintln(os.Stderr, "Database schema is up to date with declared schema files.")
+		return nil
+	}
+	fmt.Println(out)
+	WarnDropStatements(out)
+	return nil
+}
+
+func WarnDropStatements(out string) {
+	if drops := diff.FindDropStatements(out); len(drops) > 0 {
+		fmt.Fprintln(os.Stderr, "Found drop statements in schema plan. Please double check if these are expected:")
+		fmt.Fprintln(os.Stderr, utils.Yellow(strings.Join(drops, "\n")))
+	}
+}
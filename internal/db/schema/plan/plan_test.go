@@ -0,0 +1,24 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/supabase/cli/internal/db/diff"
+	"github.com/supabase/cli/internal/utils"
+)
+
+func TestSchemaPlan(t *testing.T) {
+	t.Run("throws error on missing schema paths", func(t *testing.T) {
+		utils.Config.Db.Migrations.SchemaPaths = nil
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		// Run test
+		err := Run(context.Background(), nil, pgconn.Config{}, diff.DiffSchemaMigra, fsys)
+		// Check error
+		assert.ErrorIs(t, err, diff.ErrNoDeclaredSchema)
+	})
+}
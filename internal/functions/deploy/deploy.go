@@ -17,7 +17,10 @@ import (
 	"github.com/supabase/cli/pkg/function"
 )
 
-func Run(ctx context.Context, slugs []string, useDocker bool, noVerifyJWT *bool, importMapPath string, maxJobs uint, prune bool, fsys afero.Fs) error {
+// Used by unit tests
+var newDockerBundler = NewDockerBundler
+
+func Run(ctx context.Context, slugs []string, useDocker bool, noVerifyJWT *bool, importMapPath string, maxJobs uint, cacheDir string, prune bool, fsys afero.Fs) error {
 	// Load function config and project id
 	if err := flags.LoadConfig(fsys); err != nil {
 		return err
@@ -52,15 +55,19 @@ func Run(ctx context.Context, slugs []string, useDocker bool, noVerifyJWT *bool,
 		return err
 	}
 	// Deploy new and updated functions
-	opt := function.WithMaxJobs(maxJobs)
+	var bundler function.EszipBundler
 	if useDocker {
 		if utils.IsDockerRunning(ctx) {
-			opt = function.WithBundler(NewDockerBundler(fsys))
+			bundler = newDockerBundler(fsys)
+			if len(cacheDir) > 0 {
+				version := fmt.Sprintf("%s/deno%d", utils.Config.EdgeRuntime.Image, utils.Config.EdgeRuntime.DenoVersion)
+				bundler = function.NewCachedBundler(bundler, cacheDir, fsys, version)
+			}
 		} else {
 			fmt.Fprintln(os.Stderr, utils.Yellow("WARNING:"), "Docker is not running")
 		}
 	}
-	api := function.NewEdgeRuntimeAPI(flags.ProjectRef, *utils.GetSupabase(), opt)
+	api := function.NewEdgeRuntimeAPI(flags.ProjectRef, *utils.GetSupabase(), function.WithMaxJobs(maxJobs), function.WithBundler(bundler))
 	if err := api.Deploy(ctx, functionConfig, afero.NewIOFS(fsys)); errors.Is(err, function.ErrNoDeploy) {
 		fmt.Fprintln(os.Stderr, err)
 		return nil
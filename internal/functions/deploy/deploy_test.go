@@ -2,12 +2,16 @@ package deploy
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/h2non/gock"
 	"github.com/spf13/afero"
@@ -20,6 +24,7 @@ import (
 	"github.com/supabase/cli/pkg/api"
 	"github.com/supabase/cli/pkg/cast"
 	"github.com/supabase/cli/pkg/config"
+	"github.com/supabase/cli/pkg/function"
 )
 
 func TestDeployCommand(t *testing.T) {
@@ -75,7 +80,7 @@ func TestDeployCommand(t *testing.T) {
 		}
 		// Run test
 		noVerifyJWT := true
-		err = Run(context.Background(), functions, true, &noVerifyJWT, "", 1, false, fsys)
+		err = Run(context.Background(), functions, true, &noVerifyJWT, "", 1, "", false, fsys)
 		// Check error
 		assert.NoError(t, err)
 		assert.Empty(t, apitest.ListUnmatchedRequests())
@@ -130,7 +135,7 @@ import_map = "./import_map.json"
 		outputDir := filepath.Join(utils.TempDir, fmt.Sprintf(".output_%s", slug))
 		require.NoError(t, afero.WriteFile(fsys, filepath.Join(outputDir, "output.eszip"), []byte(""), 0644))
 		// Run test
-		err = Run(context.Background(), nil, true, nil, "", 1, false, fsys)
+		err = Run(context.Background(), nil, true, nil, "", 1, "", false, fsys)
 		// Check error
 		assert.NoError(t, err)
 		assert.Empty(t, apitest.ListUnmatchedRequests())
@@ -183,7 +188,7 @@ import_map = "./import_map.json"
 		outputDir := filepath.Join(utils.TempDir, ".output_enabled-func")
 		require.NoError(t, afero.WriteFile(fsys, filepath.Join(outputDir, "output.eszip"), []byte(""), 0644))
 		// Run test
-		err = Run(context.Background(), nil, true, nil, "", 1, false, fsys)
+		err = Run(context.Background(), nil, true, nil, "", 1, "", false, fsys)
 		// Check error
 		assert.NoError(t, err)
 		assert.Empty(t, apitest.ListUnmatchedRequests())
@@ -194,7 +199,7 @@ import_map = "./import_map.json"
 		fsys := afero.NewMemMapFs()
 		require.NoError(t, utils.WriteConfig(fsys, false))
 		// Run test
-		err := Run(context.Background(), []string{"_invalid"}, true, nil, "", 1, false, fsys)
+		err := Run(context.Background(), []string{"_invalid"}, true, nil, "", 1, "", false, fsys)
 		// Check error
 		assert.ErrorContains(t, err, "Invalid Function name.")
 	})
@@ -204,7 +209,7 @@ import_map = "./import_map.json"
 		fsys := afero.NewMemMapFs()
 		require.NoError(t, utils.WriteConfig(fsys, false))
 		// Run test
-		err := Run(context.Background(), nil, true, nil, "", 1, false, fsys)
+		err := Run(context.Background(), nil, true, nil, "", 1, "", false, fsys)
 		// Check error
 		assert.ErrorContains(t, err, "No Functions specified or found in supabase/functions")
 	})
@@ -250,7 +255,7 @@ verify_jwt = false
 		outputDir := filepath.Join(utils.TempDir, fmt.Sprintf(".output_%s", slug))
 		require.NoError(t, afero.WriteFile(fsys, filepath.Join(outputDir, "output.eszip"), []byte(""), 0644))
 		// Run test
-		assert.NoError(t, Run(context.Background(), []string{slug}, true, nil, "", 1, false, fsys))
+		assert.NoError(t, Run(context.Background(), []string{slug}, true, nil, "", 1, "", false, fsys))
 		// Validate api
 		assert.Empty(t, apitest.ListUnmatchedRequests())
 	})
@@ -297,12 +302,81 @@ verify_jwt = false
 		require.NoError(t, afero.WriteFile(fsys, filepath.Join(outputDir, "output.eszip"), []byte(""), 0644))
 		// Run test
 		noVerifyJWT := false
-		assert.NoError(t, Run(context.Background(), []string{slug}, true, &noVerifyJWT, "", 1, false, fsys))
+		assert.NoError(t, Run(context.Background(), []string{slug}, true, &noVerifyJWT, "", 1, "", false, fsys))
 		// Validate api
 		assert.Empty(t, apitest.ListUnmatchedRequests())
 	})
 }
 
+// Blocks each bundle until all expected bundles are in flight.
+type concurrentBundler struct {
+	wg sync.WaitGroup
+}
+
+func (b *concurrentBundler) Bundle(ctx context.Context, slug, entrypoint, importMap string, staticFiles []string, output io.Writer) (function.FunctionDeployMetadata, error) {
+	b.wg.Done()
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		return function.FunctionDeployMetadata{}, errors.New("bundles did not run concurrently")
+	}
+	return function.FunctionDeployMetadata{Name: &slug, EntrypointPath: entrypoint}, nil
+}
+
+func TestDeployJobs(t *testing.T) {
+	flags.ProjectRef = apitest.RandomProjectRef()
+	functions := []string{"test-func", "test-func-2"}
+
+	parsed, err := url.Parse(utils.Docker.DaemonHost())
+	require.NoError(t, err)
+	parsed.Scheme = "http:"
+	dockerHost := parsed.String()
+
+	t.Run("bundles with docker in parallel jobs", func(t *testing.T) {
+		bundler := &concurrentBundler{}
+		bundler.wg.Add(len(functions))
+		newDockerBundler = func(fsys afero.Fs) function.EszipBundler { return bundler }
+		t.Cleanup(func() { newDockerBundler = NewDockerBundler })
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		require.NoError(t, utils.WriteConfig(fsys, false))
+		// Setup valid access token
+		token := apitest.RandomAccessToken(t)
+		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+		// Setup mock docker
+		require.NoError(t, apitest.MockDocker(utils.Docker))
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(dockerHost).
+			Head("/_ping").
+			Reply(http.StatusOK)
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + flags.ProjectRef + "/functions").
+			Reply(http.StatusOK).
+			JSON([]api.FunctionResponse{})
+		for i := range functions {
+			gock.New(utils.DefaultApiHost).
+				Post("/v1/projects/" + flags.ProjectRef + "/functions").
+				Reply(http.StatusCreated).
+				JSON(api.FunctionResponse{Id: fmt.Sprintf("%d", i)})
+		}
+		gock.New(utils.DefaultApiHost).
+			Put("/v1/projects/" + flags.ProjectRef + "/functions").
+			Reply(http.StatusOK).
+			JSON(api.BulkUpdateFunctionResponse{})
+		// Run test
+		err := Run(context.Background(), functions, true, nil, "", uint(len(functions)), "", false, fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+}
+
 func TestImportMapPath(t *testing.T) {
 	t.Run("loads import map from default location", func(t *testing.T) {
 		// Setup in-memory fs
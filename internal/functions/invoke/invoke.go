@@ -0,0 +1,206 @@
+package invoke
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/afero"
+	"github.com/supabase/cli/internal/gen/bearerjwt"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/flags"
+	"github.com/supabase/cli/internal/utils/tenant"
+	"github.com/supabase/cli/pkg/config"
+)
+
+type Request struct {
+	Method string
+	// Inline request body, or @path to read from a file, or @- to read from stdin
+	Data    string
+	Headers []string
+	Query   []string
+}
+
+// Auth selects the claims of the bearer token. The anon key is sent when both fields are empty.
+type Auth struct {
+	Role    string
+	Subject string
+}
+
+func Run(ctx context.Context, slug string, req Request, auth Auth, projectRef string, fsys afero.Fs) error {
+	if err := utils.ValidateFunctionSlug(slug); err != nil {
+		return err
+	}
+	if err := flags.LoadConfig(fsys); err != nil {
+		return err
+	}
+	endpoint := utils.GetApiUrl("/functions/v1/" + slug)
+	if len(projectRef) > 0 {
+		endpoint = "https://" + utils.GetSupabaseHost(projectRef) + "/functions/v1/" + slug
+	}
+	token, err := getBearerToken(ctx, auth, projectRef)
+	if err != nil {
+		return err
+	}
+	body, err := readData(req.Data, fsys)
+	if err != nil {
+		return err
+	}
+	httpReq, err := NewRequest(ctx, endpoint, req, body)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	fmt.Fprintln(os.Stderr, httpReq.Method, utils.Aqua(httpReq.URL.String()))
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return errors.Errorf("failed to invoke function: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Errorf("failed to read response: %w", err)
+	}
+	elapsed := time.Since(start)
+	// Body goes to stdout so it can be piped to other tools
+	printHeaders(resp, os.Stderr)
+	printBody(data, os.Stdout)
+	fmt.Fprintf(os.Stderr, "Completed in %s\n", elapsed.Round(time.Millisecond))
+	return nil
+}
+
+func getBearerToken(ctx context.Context, auth Auth, projectRef string) (string, error) {
+	if len(auth.Role) == 0 && len(auth.Subject) == 0 {
+		if len(projectRef) == 0 {
+			return utils.Config.Auth.AnonKey.Value, nil
+		}
+		keys, err := tenant.GetApiKeys(ctx, projectRef)
+		if err != nil {
+			return "", err
+		}
+		return keys.Anon, nil
+	}
+	claims := NewClaims(auth, time.Now())
+	if len(projectRef) == 0 {
+		return bearerjwt.NewLocalToken(claims)
+	}
+	claims.Ref = projectRef
+	return bearerjwt.NewToken(ctx, claims)
+}
+
+// NewClaims returns short-lived claims for the given role, defaulting to an authenticated user.
+func NewClaims(auth Auth, now time.Time) config.CustomClaims {
+	claims := config.CustomClaims{Role: auth.Role}
+	if len(claims.Role) == 0 {
+		claims.Role = "authenticated"
+	}
+	claims.Subject = auth.Subject
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(time.Hour))
+	// Same as gen bearer-jwt, authenticated role without user ID is anonymous
+	if strings.EqualFold(claims.Role, "authenticated") && len(claims.Subject) == 0 {
+		claims.IsAnon = true
+	}
+	return claims
+}
+
+func readData(data string, fsys afero.Fs) ([]byte, error) {
+	path, ok := strings.CutPrefix(data, "@")
+	if !ok {
+		return []byte(data), nil
+	}
+	if path == "-" {
+		body, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, errors.Errorf("failed to read stdin: %w", err)
+		}
+		return body, nil
+	}
+	body, err := afero.ReadFile(fsys, path)
+	if err != nil {
+		return nil, errors.Errorf("failed to read request body: %w", err)
+	}
+	return body, nil
+}
+
+func NewRequest(ctx context.Context, endpoint string, req Request, body []byte) (*http.Request, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Errorf("failed to parse function url: %w", err)
+	}
+	query := parsed.Query()
+	for _, kv := range req.Query {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, errors.Errorf("invalid query param (expected key=value): %s", kv)
+		}
+		query.Add(k, v)
+	}
+	parsed.RawQuery = query.Encode()
+	method := strings.ToUpper(req.Method)
+	if len(method) == 0 {
+		method = http.MethodGet
+		if len(body) > 0 {
+			method = http.MethodPost
+		}
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, parsed.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Errorf("failed to initialise request: %w", err)
+	}
+	if json.Valid(body) {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	for _, h := range req.Headers {
+		k, v, ok := strings.Cut(h, ":")
+		if !ok {
+			return nil, errors.Errorf("invalid header (expected Key: Value): %s", h)
+		}
+		httpReq.Header.Set(strings.TrimSpace(k), strings.TrimSpace(v))
+	}
+	return httpReq, nil
+}
+
+func printHeaders(resp *http.Response, w io.Writer) {
+	status := resp.Status
+	if resp.StatusCode >= http.StatusBadRequest {
+		status = utils.Red(status)
+	} else {
+		status = utils.Bold(status)
+	}
+	fmt.Fprintln(w, resp.Proto, status)
+	keys := make([]string, 0, len(resp.Header))
+	for k := range resp.Header {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		for _, v := range resp.Header.Values(k) {
+			fmt.Fprintf(w, "%s: %s\n", utils.Aqua(k), v)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+func printBody(data []byte, w io.Writer) {
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		out.Reset()
+		out.Write(data)
+	}
+	if out.Len() > 0 && !bytes.HasSuffix(out.Bytes(), []byte("\n")) {
+		out.WriteByte('\n')
+	}
+	_, _ = w.Write(out.Bytes())
+}
@@ -0,0 +1,154 @@
+package invoke
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/oapi-codegen/nullable"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/cli/internal/testing/apitest"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func TestInvokeCommand(t *testing.T) {
+	const slug = "hello-world"
+
+	t.Run("invokes local function with anon key", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		require.NoError(t, utils.WriteFile("data.json", []byte(`{"name":"world"}`), fsys))
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New("http://127.0.0.1:54321").
+			Put("/functions/v1/"+slug).
+			MatchParam("page", "2").
+			MatchHeader("Authorization", "Bearer ey").
+			MatchHeader("X-Region", "us-east-1").
+			MatchType("json").
+			BodyString(`{"name":"world"}`).
+			Reply(http.StatusOK).
+			JSON(map[string]string{"message": "Hello world!"})
+		// Run test
+		err := Run(context.Background(), slug, Request{
+			Method:  "put",
+			Data:    "@data.json",
+			Headers: []string{"X-Region: us-east-1"},
+			Query:   []string{"page=2"},
+		}, Auth{}, "", fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("invokes remote function with anon key", func(t *testing.T) {
+		project := apitest.RandomProjectRef()
+		token := apitest.RandomAccessToken(t)
+		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/api-keys").
+			Reply(http.StatusOK).
+			JSON([]api.ApiKeyResponse{{Name: "anon", ApiKey: nullable.NewNullableWithValue("anon-key")}})
+		gock.New("https://"+utils.GetSupabaseHost(project)).
+			Get("/functions/v1/"+slug).
+			MatchHeader("Authorization", "Bearer anon-key").
+			Reply(http.StatusNotFound).
+			BodyString("not found")
+		// Run test
+		err := Run(context.Background(), slug, Request{}, Auth{}, project, fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on malformed header", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		// Run test
+		err := Run(context.Background(), slug, Request{Headers: []string{"X-Region"}}, Auth{}, "", fsys)
+		// Check error
+		assert.ErrorContains(t, err, "invalid header (expected Key: Value): X-Region")
+	})
+
+	t.Run("throws error on missing body file", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		// Run test
+		err := Run(context.Background(), slug, Request{Data: "@missing.json"}, Auth{}, "", fsys)
+		// Check error
+		assert.ErrorContains(t, err, "failed to read request body:")
+	})
+
+	t.Run("throws error on malformed slug", func(t *testing.T) {
+		// Run test
+		err := Run(context.Background(), "@", Request{}, Auth{}, "", afero.NewMemMapFs())
+		// Check error
+		assert.ErrorContains(t, err, "Invalid Function name.")
+	})
+}
+
+func TestNewClaims(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	t.Run("defaults to anonymous user", func(t *testing.T) {
+		claims := NewClaims(Auth{}, now)
+		// Check claims
+		assert.Equal(t, "authenticated", claims.Role)
+		assert.True(t, claims.IsAnon)
+		assert.Equal(t, now.Add(time.Hour), claims.ExpiresAt.Time)
+	})
+
+	t.Run("sets user id", func(t *testing.T) {
+		claims := NewClaims(Auth{Subject: "user-id"}, now)
+		// Check claims
+		assert.Equal(t, "authenticated", claims.Role)
+		assert.False(t, claims.IsAnon)
+		assert.Equal(t, "user-id", claims.Subject)
+	})
+}
+
+func TestNewRequest(t *testing.T) {
+	t.Run("defaults to post with body", func(t *testing.T) {
+		req, err := NewRequest(context.Background(), "http://localhost/functions/v1/hello", Request{}, []byte("hi"))
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Empty(t, req.Header.Get("Content-Type"))
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "hi", string(body))
+	})
+
+	t.Run("throws error on malformed query", func(t *testing.T) {
+		_, err := NewRequest(context.Background(), "http://localhost/functions/v1/hello", Request{Query: []string{"page"}}, nil)
+		// Check error
+		assert.ErrorContains(t, err, "invalid query param (expected key=value): page")
+	})
+}
+
+func TestPrintBody(t *testing.T) {
+	t.Run("indents json", func(t *testing.T) {
+		var out bytes.Buffer
+		printBody([]byte(`{"message":"Hello"}`), &out)
+		// Check output
+		assert.Equal(t, "{\n  \"message\": \"Hello\"\n}\n", out.String())
+	})
+
+	t.Run("prints plain text", func(t *testing.T) {
+		var out bytes.Buffer
+		printBody([]byte("Hello"), &out)
+		// Check output
+		assert.Equal(t, "Hello\n", out.String())
+	})
+}
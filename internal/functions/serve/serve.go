@@ -122,15 +122,11 @@ func restartEdgeRuntime(ctx context.Context, envFilePath string, noVerifyJWT *bo
 
 func ServeFunctions(ctx context.Context, envFilePath string, noVerifyJWT *bool, importMapPath string, dbUrl string, runtimeOption RuntimeOption, fsys afero.Fs) error {
 	// 1. Parse custom env file
-	env, err := parseEnvFile(envFilePath, fsys)
+	env, err := RuntimeEnv(envFilePath, dbUrl, fsys)
 	if err != nil {
 		return err
 	}
 	env = append(env,
-		fmt.Sprintf("SUPABASE_URL=http://%s:8000", utils.KongAliases[0]),
-		"SUPABASE_ANON_KEY="+utils.Config.Auth.AnonKey.Value,
-		"SUPABASE_SERVICE_ROLE_KEY="+utils.Config.Auth.ServiceRoleKey.Value,
-		"SUPABASE_DB_URL="+dbUrl,
 		"SUPABASE_INTERNAL_JWT_SECRET="+utils.Config.Auth.JwtSecret.Value,
 		fmt.Sprintf("SUPABASE_INTERNAL_HOST_PORT=%d", utils.Config.Api.Port),
 	)
@@ -171,6 +167,11 @@ func ServeFunctions(ctx context.Context, envFilePath string, noVerifyJWT *bool,
 		}
 	}
 	env = append(env, "SUPABASE_INTERNAL_FUNCTIONS_CONFIG="+functionsConfigString)
+	functionsEnvString, err := PopulatePerFunctionEnv(fsys)
+	if err != nil {
+		return err
+	}
+	env = append(env, "SUPABASE_INTERNAL_FUNCTIONS_ENV="+functionsEnvString)
 	// 3. Parse entrypoint script
 	cmd := append([]string{
 		"edge-runtime",
@@ -225,6 +226,21 @@ EOF
 	return err
 }
 
+// RuntimeEnv returns the custom env file merged with the variables that connect Functions to
+// the local stack from inside the docker network.
+func RuntimeEnv(envFilePath, dbUrl string, fsys afero.Fs) ([]string, error) {
+	env, err := parseEnvFile(envFilePath, fsys)
+	if err != nil {
+		return nil, err
+	}
+	return append(env,
+		fmt.Sprintf("SUPABASE_URL=http://%s:8000", utils.KongAliases[0]),
+		"SUPABASE_ANON_KEY="+utils.Config.Auth.AnonKey.Value,
+		"SUPABASE_SERVICE_ROLE_KEY="+utils.Config.Auth.ServiceRoleKey.Value,
+		"SUPABASE_DB_URL="+dbUrl,
+	), nil
+}
+
 func parseEnvFile(envFilePath string, fsys afero.Fs) ([]string, error) {
 	if envFilePath == "" {
 		if f, err := fsys.Stat(utils.FallbackEnvFilePath); err == nil && !f.IsDir() {
@@ -275,3 +291,27 @@ func PopulatePerFunctionConfigs(cwd, importMapPath string, noVerifyJWT *bool, fs
 	}
 	return utils.RemoveDuplicates(binds), string(functionsConfigBytes), nil
 }
+
+// PopulatePerFunctionEnv returns the secrets scoped to each Function, keyed by slug. The main
+// worker merges them with the global env so that each user worker only sees its own secrets.
+func PopulatePerFunctionEnv(fsys afero.Fs) (string, error) {
+	slugs, err := deploy.GetFunctionSlugs(fsys)
+	if err != nil {
+		return "", err
+	}
+	functionsEnv := map[string]map[string]string{}
+	for _, slug := range slugs {
+		envMap, err := set.ListFunctionSecrets(slug, fsys)
+		if err != nil {
+			return "", err
+		}
+		if len(envMap) > 0 {
+			functionsEnv[slug] = envMap
+		}
+	}
+	functionsEnvBytes, err := json.Marshal(functionsEnv)
+	if err != nil {
+		return "", errors.Errorf("failed to marshal env json: %w", err)
+	}
+	return string(functionsEnvBytes), nil
+}
@@ -170,4 +170,14 @@ func TestServeFunctions(t *testing.T) {
 		}, binds)
 		assert.Equal(t, `{"hello":{"verifyJWT":true,"entrypointPath":"testdata/functions/hello/index.ts","staticFiles":["testdata/image.png"]}}`, configString)
 	})
+	t.Run("parses function env", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		require.NoError(t, utils.WriteFile("testdata/functions/hello/.env", []byte("STRIPE_KEY=sk_test"), fsys))
+		// Run test
+		envString, err := PopulatePerFunctionEnv(fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, `{"hello":{"STRIPE_KEY":"sk_test"}}`, envString)
+	})
 }
@@ -35,6 +35,10 @@ const DEBUG = Deno.env.get("SUPABASE_INTERNAL_DEBUG") === "true";
 const FUNCTIONS_CONFIG_STRING = Deno.env.get(
   "SUPABASE_INTERNAL_FUNCTIONS_CONFIG",
 )!;
+// Secrets scoped to each function, keyed by function name.
+const FUNCTIONS_ENV: Record<string, Record<string, string>> = JSON.parse(
+  Deno.env.get("SUPABASE_INTERNAL_FUNCTIONS_ENV") || "{}",
+);
 
 const WALLCLOCK_LIMIT_SEC = parseInt(
   Deno.env.get("SUPABASE_INTERNAL_WALLCLOCK_LIMIT_SEC"),
@@ -176,7 +180,10 @@ Deno.serve({
     const memoryLimitMb = 256;
     const workerTimeoutMs = isFinite(WALLCLOCK_LIMIT_SEC) ? WALLCLOCK_LIMIT_SEC * 1000 : 400 * 1000;
     const noModuleCache = false;
-    const envVarsObj = Deno.env.toObject();
+    const envVarsObj = {
+      ...Deno.env.toObject(),
+      ...FUNCTIONS_ENV[functionName],
+    };
     const envVars = Object.entries(envVarsObj)
       .filter(([name, _]) =>
         !EXCLUDED_ENVS.includes(name) && !name.startsWith("SUPABASE_INTERNAL_")
@@ -0,0 +1,123 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/go-errors/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"github.com/supabase/cli/internal/functions/deploy"
+	"github.com/supabase/cli/internal/functions/serve"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/flags"
+)
+
+const (
+	ReporterTAP   = "tap"
+	ReporterJUnit = "junit"
+
+	testFileSuffix = "_test.ts"
+)
+
+var ErrNoTests = errors.New("No test files found. Create a file ending in " + testFileSuffix + " under " + utils.FunctionsDir)
+
+func Run(ctx context.Context, paths []string, reporter, envFilePath string, fsys afero.Fs) error {
+	if err := flags.LoadConfig(fsys); err != nil {
+		return err
+	}
+	if err := utils.AssertSupabaseDbIsRunning(); err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return errors.Errorf("failed to get working directory: %w", err)
+	}
+	testFiles, err := FindTestFiles(paths, cwd, fsys)
+	if err != nil {
+		return err
+	}
+	// Use network alias because Deno cannot resolve `_` in hostname
+	dbUrl := fmt.Sprintf("postgresql://postgres:postgres@%s:5432/postgres", utils.DbAliases[0])
+	env, err := serve.RuntimeEnv(envFilePath, dbUrl, fsys)
+	if err != nil {
+		return err
+	}
+	cmd := []string{"deno", "test", "--allow-all", "--reporter=" + reporter}
+	var binds []string
+	for _, fp := range testFiles {
+		modules, err := deploy.GetBindMounts(cwd, utils.FunctionsDir, "", fp, "", fsys)
+		if err != nil {
+			return err
+		}
+		binds = append(binds, modules...)
+		cmd = append(cmd, utils.ToDockerPath(filepath.Join(cwd, fp)))
+	}
+	if viper.GetBool("DEBUG") {
+		cmd = append(cmd, "--log-level=debug")
+	}
+	fmt.Fprintf(os.Stderr, "Running %d test files with %s reporter...\n", len(testFiles), reporter)
+	// Test results are written to stdout for CI to collect
+	return utils.DockerRunOnceWithConfig(
+		ctx,
+		container.Config{
+			Image:      utils.Config.EdgeRuntime.Image,
+			Env:        env,
+			Entrypoint: cmd,
+			WorkingDir: utils.ToDockerPath(cwd),
+		},
+		container.HostConfig{
+			Binds: utils.RemoveDuplicates(binds),
+		},
+		network.NetworkingConfig{},
+		"",
+		os.Stdout,
+		os.Stderr,
+	)
+}
+
+// FindTestFiles resolves test files under the given paths, defaulting to all Functions. Paths
+// are relative to the directory where the command was invoked, and results are relative to cwd.
+func FindTestFiles(paths []string, cwd string, fsys afero.Fs) ([]string, error) {
+	if len(paths) == 0 {
+		paths = []string{filepath.Join(cwd, utils.FunctionsDir)}
+	}
+	var result []string
+	for _, fp := range paths {
+		if !filepath.IsAbs(fp) {
+			fp = filepath.Join(utils.CurrentDirAbs, fp)
+		}
+		root, err := filepath.Rel(cwd, fp)
+		if err != nil {
+			return nil, errors.Errorf("failed to resolve relative path: %w", err)
+		}
+		if err := afero.Walk(fsys, root, func(path string, info fs.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				// Skip hidden directories, ie. .vscode
+				if name := info.Name(); path != root && strings.HasPrefix(name, ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(path, testFileSuffix) || path == root {
+				result = append(result, path)
+			}
+			return nil
+		}); err != nil {
+			return nil, errors.Errorf("failed to find test files: %w", err)
+		}
+	}
+	if len(result) == 0 {
+		return nil, errors.New(ErrNoTests)
+	}
+	return result, nil
+}
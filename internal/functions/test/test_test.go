@@ -0,0 +1,108 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/h2non/gock"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/cli/internal/testing/apitest"
+	"github.com/supabase/cli/internal/utils"
+)
+
+func TestRunCommand(t *testing.T) {
+	testFile := filepath.Join(utils.FunctionsDir, "hello", "index_test.ts")
+
+	t.Run("runs tests with deno", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		require.NoError(t, utils.WriteFile(utils.ConfigPath, []byte(`project_id = "test"`), fsys))
+		require.NoError(t, utils.WriteFile(testFile, []byte(`Deno.test("hello", () => {})`), fsys))
+		// Setup mock docker
+		require.NoError(t, apitest.MockDocker(utils.Docker))
+		defer gock.OffAll()
+		gock.New(utils.Docker.DaemonHost()).
+			Get("/v" + utils.Docker.ClientVersion() + "/containers/supabase_db_test/json").
+			Reply(http.StatusOK).
+			JSON(container.InspectResponse{})
+		containerId := "test-deno"
+		apitest.MockDockerStart(utils.Docker, utils.GetRegistryImageUrl(utils.Config.EdgeRuntime.Image), containerId)
+		require.NoError(t, apitest.MockDockerLogs(utils.Docker, containerId, "ok 1 - hello"))
+		// Run test
+		err := Run(context.Background(), nil, ReporterTAP, "", fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on missing database", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		require.NoError(t, utils.WriteFile(utils.ConfigPath, []byte(`project_id = "test"`), fsys))
+		// Setup mock docker
+		require.NoError(t, apitest.MockDocker(utils.Docker))
+		defer gock.OffAll()
+		gock.New(utils.Docker.DaemonHost()).
+			Get("/v" + utils.Docker.ClientVersion() + "/containers/supabase_db_test/json").
+			Reply(http.StatusNotFound)
+		// Run test
+		err := Run(context.Background(), nil, ReporterTAP, "", fsys)
+		// Check error
+		assert.ErrorIs(t, err, utils.ErrNotRunning)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+}
+
+func TestFindTestFiles(t *testing.T) {
+	cwd := utils.CurrentDirAbs
+	fsys := afero.NewMemMapFs()
+	for _, fp := range []string{
+		filepath.Join(utils.FunctionsDir, "hello", "index.ts"),
+		filepath.Join(utils.FunctionsDir, "hello", "index_test.ts"),
+		filepath.Join(utils.FunctionsDir, "_shared", "cors_test.ts"),
+		filepath.Join(utils.FunctionsDir, ".vscode", "ignored_test.ts"),
+		filepath.Join("tests", "e2e.ts"),
+	} {
+		require.NoError(t, utils.WriteFile(fp, []byte{}, fsys))
+	}
+
+	t.Run("discovers all test files", func(t *testing.T) {
+		files, err := FindTestFiles(nil, cwd, fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			filepath.Join(utils.FunctionsDir, "hello", "index_test.ts"),
+			filepath.Join(utils.FunctionsDir, "_shared", "cors_test.ts"),
+		}, files)
+	})
+
+	t.Run("accepts explicit paths", func(t *testing.T) {
+		files, err := FindTestFiles([]string{filepath.Join(utils.FunctionsDir, "hello"), filepath.Join("tests", "e2e.ts")}, cwd, fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			filepath.Join(utils.FunctionsDir, "hello", "index_test.ts"),
+			filepath.Join("tests", "e2e.ts"),
+		}, files)
+	})
+
+	t.Run("throws error on missing path", func(t *testing.T) {
+		_, err := FindTestFiles(nil, cwd, afero.NewMemMapFs())
+		// Check error
+		assert.ErrorContains(t, err, "failed to find test files")
+	})
+
+	t.Run("throws error on empty directory", func(t *testing.T) {
+		empty := afero.NewMemMapFs()
+		require.NoError(t, empty.MkdirAll(utils.FunctionsDir, 0755))
+		// Run test
+		_, err := FindTestFiles(nil, cwd, empty)
+		// Check error
+		assert.ErrorIs(t, err, ErrNoTests)
+	})
+}
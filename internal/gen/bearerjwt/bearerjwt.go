@@ -20,11 +20,7 @@ func Run(ctx context.Context, claims jwt.Claims, w io.Writer, fsys afero.Fs) err
 	if err := flags.LoadConfig(fsys); err != nil {
 		return err
 	}
-	key, err := getSigningKey(ctx)
-	if err != nil {
-		return err
-	}
-	token, err := config.GenerateAsymmetricJWT(*key, claims)
+	token, err := NewToken(ctx, claims)
 	if err != nil {
 		return err
 	}
@@ -32,6 +28,27 @@ func Run(ctx context.Context, claims jwt.Claims, w io.Writer, fsys afero.Fs) err
 	return nil
 }
 
+// NewToken signs claims with a signing key selected from config, prompting for one if needed.
+func NewToken(ctx context.Context, claims jwt.Claims) (string, error) {
+	key, err := getSigningKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	return config.GenerateAsymmetricJWT(*key, claims)
+}
+
+// NewLocalToken signs claims with the same key as the api keys of the local stack.
+func NewLocalToken(claims config.CustomClaims) (string, error) {
+	if len(utils.Config.Auth.SigningKeysPath) > 0 && len(utils.Config.Auth.SigningKeys) > 0 {
+		return config.GenerateAsymmetricJWT(utils.Config.Auth.SigningKeys[0], claims)
+	}
+	token, err := claims.NewToken().SignedString([]byte(utils.Config.Auth.JwtSecret.Value))
+	if err != nil {
+		return "", errors.Errorf("failed to sign token: %w", err)
+	}
+	return token, nil
+}
+
 func getSigningKey(ctx context.Context) (*config.JWK, error) {
 	console := utils.NewConsole()
 	if len(utils.Config.Auth.SigningKeysPath) == 0 {
@@ -192,3 +192,22 @@ func TestGenerateToken(t *testing.T) {
 		assert.ErrorContains(t, err, "signing key not found: test-key")
 	})
 }
+
+func TestNewLocalToken(t *testing.T) {
+	t.Run("signs with jwt secret by default", func(t *testing.T) {
+		utils.Config.Auth.SigningKeysPath = ""
+		utils.Config.Auth.JwtSecret.Value = "test-secret-at-least-32-characters-long"
+		claims := config.CustomClaims{Role: "authenticated"}
+		claims.Subject = "test-user"
+		// Run test
+		signed, err := NewLocalToken(claims)
+		// Check error
+		assert.NoError(t, err)
+		token, err := jwt.NewParser().Parse(signed, func(t *jwt.Token) (any, error) {
+			return []byte(utils.Config.Auth.JwtSecret.Value), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "HS256", token.Header["alg"])
+		assert.Equal(t, "test-user", token.Claims.(jwt.MapClaims)["sub"])
+	})
+}
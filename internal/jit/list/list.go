@@ -0,0 +1,89 @@
+package list
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/utils"
+)
+
+func Run(ctx context.Context, projectRef string) error {
+	resp, err := utils.GetSupabase().V1ListJitAccessWithResponse(ctx, projectRef)
+	if err != nil {
+		return errors.Errorf("failed to list JIT access: %w", err)
+	} else if resp.JSON200 == nil {
+		return errors.Errorf("unexpected list JIT access status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	var result struct {
+		Items []Access `json:"items"`
+	}
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return errors.Errorf("failed to parse JIT access: %w", err)
+	}
+	if utils.OutputFormat.Value != utils.OutputPretty {
+		return utils.EncodeOutput(utils.OutputFormat.Value, os.Stdout, result)
+	}
+	table := `|USER ID|ROLE|EXPIRES AT|ALLOWED NETWORKS|
+|-|-|-|-|
+`
+	for _, item := range result.Items {
+		for _, r := range item.UserRoles {
+			var cidrs []string
+			if n := r.AllowedNetworks; n != nil {
+				for _, c := range n.AllowedCidrs {
+					cidrs = append(cidrs, c.Cidr)
+				}
+				for _, c := range n.AllowedCidrsV6 {
+					cidrs = append(cidrs, c.Cidr)
+				}
+			}
+			networks := "-"
+			if len(cidrs) > 0 {
+				networks = strings.Join(cidrs, ", ")
+			}
+			table += fmt.Sprintf(
+				"|`%s`|`%s`|`%s`|`%s`|\n",
+				item.UserId,
+				r.Role,
+				FormatExpiry(r.ExpiresAt),
+				networks,
+			)
+		}
+	}
+	return utils.RenderTable(table)
+}
+
+type cidr struct {
+	Cidr string `json:"cidr"`
+}
+
+type allowedNetworks struct {
+	AllowedCidrs   []cidr `json:"allowed_cidrs,omitempty"`
+	AllowedCidrsV6 []cidr `json:"allowed_cidrs_v6,omitempty"`
+}
+
+// Grant mirrors the generated role type, which decodes expires_at as float32 and so
+// truncates unix timestamps to the nearest 128 seconds.
+type Grant struct {
+	AllowedNetworks *allowedNetworks `json:"allowed_networks,omitempty"`
+	ExpiresAt       *float64         `json:"expires_at,omitempty"`
+	Role            string           `json:"role"`
+}
+
+type Access struct {
+	UserId    string  `json:"user_id"`
+	UserRoles []Grant `json:"user_roles"`
+}
+
+// FormatExpiry renders a unix timestamp in seconds as RFC3339.
+func FormatExpiry(expiresAt *float64) string {
+	if expiresAt == nil {
+		return "never"
+	}
+	return time.Unix(int64(*expiresAt), 0).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,18 @@
+package list
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supabase/cli/pkg/cast"
+)
+
+func TestFormatExpiry(t *testing.T) {
+	t.Run("keeps second precision", func(t *testing.T) {
+		assert.Equal(t, "2023-11-14T22:13:21Z", FormatExpiry(cast.Ptr(float64(1700000001))))
+	})
+
+	t.Run("formats missing expiry", func(t *testing.T) {
+		assert.Equal(t, "never", FormatExpiry(nil))
+	})
+}
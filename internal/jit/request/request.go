@@ -0,0 +1,53 @@
+package request
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/jit/list"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+)
+
+var ErrMissingRhost = errors.New("Missing public IP address to authorise JIT access from. Use --rhost or set SUPABASE_DB_JIT_RHOST.")
+
+// Run authorises a JIT grant of role for the given host. The grant is created atomically by
+// the API, which also decides its expiry.
+func Run(ctx context.Context, projectRef, role, rhost string) error {
+	if len(rhost) == 0 {
+		return errors.New(ErrMissingRhost)
+	}
+	body := api.AuthorizeJitAccessBody{Role: role, Rhost: rhost}
+	resp, err := utils.GetSupabase().V1AuthorizeJitAccessWithResponse(ctx, projectRef, body)
+	if err != nil {
+		return errors.Errorf("failed to request JIT access: %w", err)
+	} else if resp.JSON200 == nil {
+		return errors.Errorf("unexpected request JIT access status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	var result struct {
+		UserId   string     `json:"user_id"`
+		UserRole list.Grant `json:"user_role"`
+	}
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return errors.Errorf("failed to parse JIT access: %w", err)
+	}
+	if utils.OutputFormat.Value != utils.OutputPretty {
+		return utils.EncodeOutput(utils.OutputFormat.Value, os.Stdout, result)
+	}
+	fmt.Fprintf(os.Stderr, "Granted role %s until %s\n", utils.Aqua(result.UserRole.Role), list.FormatExpiry(result.UserRole.ExpiresAt))
+	return nil
+}
+
+// GetAccess returns the JIT grants of the current user.
+func GetAccess(ctx context.Context, projectRef string) (api.JitAccessResponse, error) {
+	resp, err := utils.GetSupabase().V1GetJitAccessWithResponse(ctx, projectRef)
+	if err != nil {
+		return api.JitAccessResponse{}, errors.Errorf("failed to get JIT access: %w", err)
+	} else if resp.JSON200 == nil {
+		return api.JitAccessResponse{}, errors.Errorf("unexpected get JIT access status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	return *resp.JSON200, nil
+}
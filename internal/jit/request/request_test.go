@@ -0,0 +1,61 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+	"github.com/supabase/cli/internal/testing/apitest"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func TestRequestCommand(t *testing.T) {
+	// Setup valid access token
+	token := apitest.RandomAccessToken(t)
+	t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+	// Setup valid project ref
+	project := apitest.RandomProjectRef()
+	userId := uuid.New()
+
+	t.Run("authorises grant of role", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/" + project + "/database/jit").
+			JSON(api.AuthorizeJitAccessBody{Role: "postgres", Rhost: "203.0.113.7"}).
+			Reply(http.StatusOK).
+			JSON(map[string]any{"user_id": userId, "user_role": map[string]any{
+				"role":       "postgres",
+				"expires_at": 1700000001,
+			}})
+		// Run test
+		err := Run(context.Background(), project, "postgres", "203.0.113.7")
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on missing host", func(t *testing.T) {
+		// Run test
+		err := Run(context.Background(), project, "postgres", "")
+		// Check error
+		assert.ErrorIs(t, err, ErrMissingRhost)
+	})
+
+	t.Run("throws error on service unavailable", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/" + project + "/database/jit").
+			Reply(http.StatusServiceUnavailable)
+		// Run test
+		err := Run(context.Background(), project, "postgres", "203.0.113.7")
+		// Check error
+		assert.ErrorContains(t, err, "unexpected request JIT access status 503:")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+}
@@ -0,0 +1,38 @@
+package revoke
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/supabase/cli/internal/jit/request"
+	"github.com/supabase/cli/internal/utils"
+)
+
+func Run(ctx context.Context, projectRef, userId string) error {
+	var id uuid.UUID
+	if len(userId) > 0 {
+		var err error
+		if id, err = uuid.Parse(userId); err != nil {
+			return errors.Errorf("failed to parse user ID: %w", err)
+		}
+	} else {
+		// Revoke grants of the current user by default
+		current, err := request.GetAccess(ctx, projectRef)
+		if err != nil {
+			return err
+		}
+		id = current.UserId
+	}
+	resp, err := utils.GetSupabase().V1DeleteJitAccessWithResponse(ctx, projectRef, id)
+	if err != nil {
+		return errors.Errorf("failed to revoke JIT access: %w", err)
+	} else if resp.StatusCode() != http.StatusOK {
+		return errors.Errorf("unexpected revoke JIT access status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	fmt.Fprintln(os.Stderr, "Revoked JIT access of user:", utils.Aqua(id.String()))
+	return nil
+}
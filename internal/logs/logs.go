@@ -0,0 +1,288 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+)
+
+const (
+	SourcePostgres  = "postgres"
+	SourceAuth      = "auth"
+	SourceFunctions = "functions"
+	SourceEdge      = "edge"
+	SourceApi       = "api"
+	SourceStorage   = "storage"
+	SourceRealtime  = "realtime"
+	SourcePostgrest = "postgrest"
+)
+
+// Maps each user facing source to its analytics table.
+var sourceTables = map[string]string{
+	SourcePostgres:  "postgres_logs",
+	SourceAuth:      "auth_logs",
+	SourceFunctions: "function_logs",
+	SourceEdge:      "function_edge_logs",
+	SourceApi:       "edge_logs",
+	SourceStorage:   "storage_logs",
+	SourceRealtime:  "realtime_logs",
+	SourcePostgrest: "postgrest_logs",
+}
+
+var Sources = []string{
+	SourcePostgres,
+	SourceAuth,
+	SourceFunctions,
+	SourceEdge,
+	SourceApi,
+	SourceStorage,
+	SourceRealtime,
+	SourcePostgrest,
+}
+
+type Params struct {
+	Source string
+	Sql    string
+	Filter string
+	Limit  uint
+	Start  time.Time
+	End    time.Time
+	Follow bool
+}
+
+var ErrFollowColumns = errors.New("Following logs requires the query to select both id and timestamp columns.")
+
+// Used by unit tests
+var pollInterval = 5 * time.Second
+
+func Run(ctx context.Context, projectRef string, params Params, w io.Writer) error {
+	query, err := buildQuery(params)
+	if err != nil {
+		return err
+	}
+	// Entries after now are left for follow mode to poll
+	until := params.End
+	if now := time.Now().UTC(); params.End.IsZero() || params.End.After(now) {
+		params.End = now
+	}
+	entries, err := queryLogs(ctx, projectRef, query, params.Start, params.End)
+	if err != nil {
+		return err
+	} else if params.Follow {
+		if err := assertFollowColumns(entries); err != nil {
+			return err
+		}
+	}
+	if err := printLogs(entries, w); err != nil || !params.Follow {
+		return err
+	}
+	// Polls are not limited so that bursts within an interval are not dropped
+	params.Limit = 0
+	if query, err = buildQuery(params); err != nil {
+		return err
+	}
+	return followLogs(ctx, projectRef, query, entries, params.End, until, w)
+}
+
+// Polls for new entries until the user interrupts or the until time is reached.
+func followLogs(ctx context.Context, projectRef, query string, entries []LogEntry, start, until time.Time, w io.Writer) error {
+	seen := map[string]struct{}{}
+	for _, e := range entries {
+		seen[e.Id] = struct{}{}
+	}
+	if last := latestTimestamp(entries); last.After(start) {
+		start = last
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for until.IsZero() || start.Before(until) {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		end := time.Now().UTC()
+		if !until.IsZero() && end.After(until) {
+			end = until
+		}
+		entries, err := queryLogs(ctx, projectRef, query, start, end)
+		if ctx.Err() != nil {
+			return nil
+		} else if err != nil {
+			return err
+		} else if err := assertFollowColumns(entries); err != nil {
+			return err
+		}
+		var fresh []LogEntry
+		for _, e := range entries {
+			if _, ok := seen[e.Id]; ok && len(e.Id) > 0 {
+				continue
+			}
+			seen[e.Id] = struct{}{}
+			fresh = append(fresh, e)
+		}
+		if err := printLogs(fresh, w); err != nil {
+			return err
+		}
+		if end.Equal(until) {
+			return nil
+		}
+		// Entries at the window boundary are deduplicated by id
+		if last := latestTimestamp(fresh); last.After(start) {
+			start = last
+		}
+	}
+	return nil
+}
+
+// Custom queries must return the columns used to deduplicate entries and advance the window.
+func assertFollowColumns(entries []LogEntry) error {
+	for _, e := range entries {
+		if len(e.Id) == 0 || e.Timestamp.IsZero() {
+			utils.CmdSuggestion = fmt.Sprintf("Add %s to the select list of your --sql query.", utils.Aqua("id, timestamp"))
+			return errors.New(ErrFollowColumns)
+		}
+	}
+	return nil
+}
+
+func buildQuery(params Params) (string, error) {
+	if len(params.Sql) > 0 {
+		return params.Sql, nil
+	}
+	table, ok := sourceTables[params.Source]
+	if !ok {
+		return "", errors.Errorf("unsupported log source: %s", params.Source)
+	}
+	query := "select id, timestamp, event_message from " + table
+	if filter := strings.TrimSpace(params.Filter); len(filter) > 0 {
+		query += " where " + filter
+	}
+	query += " order by timestamp desc"
+	if params.Limit > 0 {
+		query += fmt.Sprintf(" limit %d", params.Limit)
+	}
+	return query, nil
+}
+
+type LogEntry struct {
+	Id        string         `json:"id"`
+	Timestamp time.Time      `json:"timestamp"`
+	Message   string         `json:"event_message"`
+	Fields    map[string]any `json:"fields,omitempty"`
+}
+
+func queryLogs(ctx context.Context, projectRef, query string, start, end time.Time) ([]LogEntry, error) {
+	params := api.V1GetProjectLogsParams{Sql: &query, IsoTimestampEnd: &end}
+	if !start.IsZero() {
+		params.IsoTimestampStart = &start
+	}
+	resp, err := utils.GetSupabase().V1GetProjectLogsWithResponse(ctx, projectRef, &params)
+	if err != nil {
+		return nil, errors.Errorf("failed to query logs: %w", err)
+	} else if resp.JSON200 == nil {
+		return nil, errors.Errorf("unexpected query logs status %d: %s", resp.StatusCode(), string(resp.Body))
+	} else if resp.JSON200.Error != nil {
+		return nil, errors.Errorf("failed to query logs: %s", formatError(resp.JSON200.Error))
+	}
+	var result []LogEntry
+	if resp.JSON200.Result != nil {
+		for _, row := range *resp.JSON200.Result {
+			if fields, ok := row.(map[string]any); ok {
+				result = append(result, toLogEntry(fields))
+			}
+		}
+	}
+	// Analytics returns the latest entries first
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result, nil
+}
+
+func formatError(e *api.AnalyticsResponse_Error) string {
+	if msg, err := e.AsAnalyticsResponseError0(); err == nil && len(msg) > 0 {
+		return msg
+	}
+	if detail, err := e.AsAnalyticsResponseError1(); err == nil && len(detail.Message) > 0 {
+		return detail.Message
+	}
+	data, _ := e.MarshalJSON()
+	return string(data)
+}
+
+func toLogEntry(fields map[string]any) LogEntry {
+	var entry LogEntry
+	if id, ok := fields["id"].(string); ok {
+		entry.Id = id
+		delete(fields, "id")
+	}
+	switch ts := fields["timestamp"].(type) {
+	case float64:
+		// Analytics timestamps are in microseconds since epoch
+		entry.Timestamp = time.UnixMicro(int64(ts)).UTC()
+		delete(fields, "timestamp")
+	case string:
+		if parsed, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			entry.Timestamp = parsed.UTC()
+			delete(fields, "timestamp")
+		}
+	}
+	if msg, ok := fields["event_message"].(string); ok {
+		entry.Message = msg
+		delete(fields, "event_message")
+	}
+	if len(fields) > 0 {
+		entry.Fields = fields
+	}
+	return entry
+}
+
+func latestTimestamp(entries []LogEntry) (result time.Time) {
+	for _, e := range entries {
+		if e.Timestamp.After(result) {
+			result = e.Timestamp
+		}
+	}
+	return result
+}
+
+func printLogs(entries []LogEntry, w io.Writer) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	if utils.OutputFormat.Value != utils.OutputPretty {
+		return utils.EncodeOutput(utils.OutputFormat.Value, w, entries)
+	}
+	for _, e := range entries {
+		line := strings.TrimRight(e.Message, "\n")
+		if !e.Timestamp.IsZero() {
+			line = e.Timestamp.Format(time.RFC3339Nano) + " " + line
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return errors.Errorf("failed to print logs: %w", err)
+		}
+	}
+	return nil
+}
+
+func ParseTime(value string) (time.Time, error) {
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().UTC().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return t, errors.Errorf("failed to parse time %q: must be RFC3339 timestamp or duration", value)
+	}
+	return t.UTC(), nil
+}
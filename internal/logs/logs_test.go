@@ -0,0 +1,215 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/cli/internal/testing/apitest"
+	"github.com/supabase/cli/internal/utils"
+)
+
+func TestQueryLogs(t *testing.T) {
+	// Setup valid access token
+	token := apitest.RandomAccessToken(t)
+	t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+	// Setup valid project ref
+	project := apitest.RandomProjectRef()
+
+	t.Run("prints log entries in chronological order", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/"+project+"/analytics/endpoints/logs.all").
+			MatchParam("sql", "select id, timestamp, event_message from postgres_logs where error_severity = 'ERROR' order by timestamp desc limit 10").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"result": []map[string]any{{
+				"id":            "b",
+				"timestamp":     1700000001000000,
+				"event_message": "second",
+			}, {
+				"id":            "a",
+				"timestamp":     1700000000000000,
+				"event_message": "first",
+			}}})
+		// Run test
+		var out bytes.Buffer
+		err := Run(context.Background(), project, Params{
+			Source: SourcePostgres,
+			Filter: "error_severity = 'ERROR'",
+			Limit:  10,
+		}, &out)
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, "2023-11-14T22:13:20Z first\n2023-11-14T22:13:21Z second\n", out.String())
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("polls for new entries in follow mode", func(t *testing.T) {
+		pollInterval = time.Millisecond
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/analytics/endpoints/logs.all").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"result": []map[string]any{{
+				"id":            "a",
+				"timestamp":     1700000000000000,
+				"event_message": "first",
+			}}})
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/analytics/endpoints/logs.all").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"result": []map[string]any{{
+				"id":            "a",
+				"timestamp":     1700000000000000,
+				"event_message": "first",
+			}, {
+				"id":            "b",
+				"timestamp":     1700000001000000,
+				"event_message": "second",
+			}}})
+		// Run test
+		out := cancelWriter{cancel: cancel, until: "second"}
+		err := Run(ctx, project, Params{Sql: "select * from edge_logs", Follow: true}, &out)
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, "2023-11-14T22:13:20Z first\n2023-11-14T22:13:21Z second\n", out.buf.String())
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("follows without limit until end time", func(t *testing.T) {
+		pollInterval = time.Millisecond
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/"+project+"/analytics/endpoints/logs.all").
+			MatchParam("sql", "select id, timestamp, event_message from auth_logs order by timestamp desc limit 1$").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"result": []map[string]any{{
+				"id":            "a",
+				"timestamp":     1700000000000000,
+				"event_message": "first",
+			}}})
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/"+project+"/analytics/endpoints/logs.all").
+			MatchParam("sql", "select id, timestamp, event_message from auth_logs order by timestamp desc$").
+			Persist().
+			Reply(http.StatusOK).
+			JSON(map[string]any{"result": []map[string]any{{
+				"id":            "b",
+				"timestamp":     1700000001000000,
+				"event_message": "second",
+			}, {
+				"id":            "c",
+				"timestamp":     1700000002000000,
+				"event_message": "third",
+			}}})
+		// Run test
+		var out bytes.Buffer
+		err := Run(ctx, project, Params{
+			Source: SourceAuth,
+			Limit:  1,
+			End:    time.Now().UTC().Add(100 * time.Millisecond),
+			Follow: true,
+		}, &out)
+		// Check error
+		assert.NoError(t, err)
+		assert.NoError(t, ctx.Err())
+		assert.Equal(t, "2023-11-14T22:13:20Z first\n2023-11-14T22:13:21Z second\n2023-11-14T22:13:22Z third\n", out.String())
+	})
+
+	t.Run("throws error on follow without id column", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/analytics/endpoints/logs.all").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"result": []map[string]any{{
+				"timestamp":     1700000000000000,
+				"event_message": "first",
+			}}})
+		// Run test
+		var out bytes.Buffer
+		err := Run(context.Background(), project, Params{Sql: "select timestamp, event_message from edge_logs", Follow: true}, &out)
+		// Check error
+		assert.ErrorIs(t, err, ErrFollowColumns)
+		assert.Empty(t, out.String())
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on query error", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/analytics/endpoints/logs.all").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"error": "syntax error"})
+		// Run test
+		err := Run(context.Background(), project, Params{Source: SourceAuth}, &bytes.Buffer{})
+		// Check error
+		assert.ErrorContains(t, err, "failed to query logs: syntax error")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on network error", func(t *testing.T) {
+		errNetwork := errors.New("network error")
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/analytics/endpoints/logs.all").
+			ReplyError(errNetwork)
+		// Run test
+		err := Run(context.Background(), project, Params{Source: SourceStorage}, &bytes.Buffer{})
+		// Check error
+		assert.ErrorIs(t, err, errNetwork)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on unknown source", func(t *testing.T) {
+		err := Run(context.Background(), project, Params{Source: "invalid"}, &bytes.Buffer{})
+		assert.ErrorContains(t, err, "unsupported log source: invalid")
+	})
+}
+
+func TestParseTime(t *testing.T) {
+	t.Run("parses relative duration", func(t *testing.T) {
+		result, err := ParseTime("1h")
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(-time.Hour), result, time.Minute)
+	})
+
+	t.Run("parses RFC3339 timestamp", func(t *testing.T) {
+		result, err := ParseTime("2024-01-02T03:04:05Z")
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), result)
+	})
+
+	t.Run("throws error on invalid value", func(t *testing.T) {
+		_, err := ParseTime("yesterday")
+		assert.ErrorContains(t, err, `failed to parse time "yesterday"`)
+	})
+}
+
+type cancelWriter struct {
+	buf    bytes.Buffer
+	cancel context.CancelFunc
+	until  string
+}
+
+func (w *cancelWriter) Write(p []byte) (int, error) {
+	if bytes.Contains(p, []byte(w.until)) {
+		defer w.cancel()
+	}
+	return w.buf.Write(p)
+}
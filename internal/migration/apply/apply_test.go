@@ -31,7 +31,7 @@ func TestMigrateDatabase(t *testing.T) {
 			Reply("RESET").
 			Query(sql).
 			Reply("CREATE SCHEMA").
-			Query(migration.INSERT_MIGRATION_VERSION, "0", "test", []string{sql}).
+			Query(migration.INSERT_MIGRATION_VERSION, "0", "test", []string{sql}, helper.MigrationHash(sql)).
 			Reply("INSERT 0 1")
 		// Run test
 		err := MigrateAndSeed(context.Background(), "", conn.MockClient(t), fsys)
@@ -56,7 +56,7 @@ func TestMigrateDatabase(t *testing.T) {
 			Reply("RESET").
 			Query(sql).
 			Reply("CREATE SCHEMA").
-			Query(migration.INSERT_MIGRATION_VERSION, "0", "test", []string{sql}).
+			Query(migration.INSERT_MIGRATION_VERSION, "0", "test", []string{sql}, helper.MigrationHash(sql)).
 			Reply("INSERT 0 1")
 		utils.Config.Db.Seed.Enabled = false
 		// Run test
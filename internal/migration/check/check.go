@@ -0,0 +1,396 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	mg "github.com/multigres/multigres/go/parser"
+	"github.com/multigres/multigres/go/parser/ast"
+	"github.com/spf13/afero"
+	"github.com/supabase/cli/internal/migration/up"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/migration"
+)
+
+const (
+	LevelWarning = "warning"
+	LevelError   = "error"
+
+	LIST_TABLE_SIZES = `SELECT n.nspname, c.relname, c.reltuples::bigint
+FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind IN ('r', 'p', 'm') AND n.nspname NOT IN ('pg_catalog', 'information_schema') AND n.nspname NOT LIKE 'pg_toast%'`
+)
+
+var AllowedLevels = []string{
+	LevelWarning,
+	LevelError,
+}
+
+func toEnum(level string) int {
+	return slices.Index(AllowedLevels, level)
+}
+
+type Finding struct {
+	Path    string
+	Level   string
+	Rule    string
+	Message string
+}
+
+// Sizes maps qualified table names to their estimated row counts.
+type Sizes map[string]int64
+
+// Postgres reports -1 for tables that have never been vacuumed or analyzed.
+const unknownRows = -1
+
+func Run(ctx context.Context, largeTableRows int64, failOn string, config pgconn.Config, fsys afero.Fs, options ...func(*pgx.ConnConfig)) error {
+	conn, err := utils.ConnectByConfig(ctx, config, options...)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+	pending, err := up.GetPendingMigrations(ctx, true, conn, fsys)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		fmt.Fprintln(os.Stderr, "No pending migrations to check.")
+		return nil
+	}
+	sizes, err := ListTableSizes(ctx, conn)
+	if err != nil {
+		return err
+	}
+	checker := NewChecker(sizes, largeTableRows, utils.Config.Api.Schemas)
+	var findings []Finding
+	for _, path := range pending {
+		file, err := migration.NewMigrationFromFile(path, afero.NewIOFS(fsys))
+		if err != nil {
+			return err
+		}
+		findings = append(findings, checker.CheckFile(path, file.Statements)...)
+	}
+	if len(findings) == 0 {
+		fmt.Fprintln(os.Stderr, "No unsafe statements found in pending migrations.")
+		return nil
+	}
+	if err := utils.RenderTable(makeTable(findings)); err != nil {
+		return err
+	}
+	// Check for fail-on condition
+	if failOnLevel := toEnum(failOn); failOnLevel != -1 {
+		for _, f := range findings {
+			if toEnum(f.Level) >= failOnLevel {
+				return errors.Errorf("fail-on is set to %s, non-zero exit", AllowedLevels[failOnLevel])
+			}
+		}
+	}
+	return nil
+}
+
+func ListTableSizes(ctx context.Context, conn *pgx.Conn) (Sizes, error) {
+	rows, err := conn.Query(ctx, LIST_TABLE_SIZES)
+	if err != nil {
+		return nil, errors.Errorf("failed to list table sizes: %w", err)
+	}
+	defer rows.Close()
+	result := Sizes{}
+	for rows.Next() {
+		var schema, table string
+		var count int64
+		if err := rows.Scan(&schema, &table, &count); err != nil {
+			return nil, errors.Errorf("failed to scan table sizes: %w", err)
+		}
+		result[schema+"."+table] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Errorf("failed to list table sizes: %w", err)
+	}
+	return result, nil
+}
+
+type Checker struct {
+	sizes          Sizes
+	largeTableRows int64
+	exposed        []string
+}
+
+func NewChecker(sizes Sizes, largeTableRows int64, exposedSchemas []string) *Checker {
+	return &Checker{
+		sizes:          sizes,
+		largeTableRows: largeTableRows,
+		exposed:        exposedSchemas,
+	}
+}
+
+// CheckFile walks the parsed statements of a single migration file. Tables created by
+// earlier statements are tracked as empty so that subsequent DDL on them is not flagged.
+func (c *Checker) CheckFile(path string, statements []string) []Finding {
+	var result []Finding
+	for _, sql := range statements {
+		parsed, err := mg.ParseSQL(sql)
+		if err != nil || len(parsed) == 0 {
+			// Statements unsupported by the parser cannot be checked
+			fmt.Fprintln(utils.GetDebugLogger(), "Skipped checking statement:", err)
+			continue
+		}
+		// Check every node in case the splitter kept multiple statements together
+		for _, node := range parsed {
+			for _, f := range c.checkNode(node) {
+				f.Path = filepath.Base(path)
+				result = append(result, f)
+			}
+		}
+	}
+	return result
+}
+
+func (c *Checker) checkNode(node ast.Node) (result []Finding) {
+	switch v := node.(type) {
+	case *ast.CreateStmt:
+		c.sizes[qualify(v.Relation)] = 0
+	case *ast.IndexStmt:
+		if !v.Concurrent {
+			name := qualify(v.Relation)
+			if level, ok := c.lockLevel(name); ok {
+				result = append(result, Finding{
+					Level:   level,
+					Rule:    "create-index",
+					Message: fmt.Sprintf("CREATE INDEX blocks writes to %s until the build completes. Use CREATE INDEX CONCURRENTLY with the "+migration.NoTransactionDirective+" directive instead.", c.describe(name)),
+				})
+			}
+		}
+	case *ast.AlterTableStmt:
+		if v.Objtype != ast.OBJECT_TABLE || v.Cmds == nil {
+			break
+		}
+		name := qualify(v.Relation)
+		flagged := false
+		for _, item := range v.Cmds.Items {
+			cmd, ok := item.(*ast.AlterTableCmd)
+			if !ok {
+				continue
+			}
+			if f, ok := c.checkAlterCmd(name, cmd); ok {
+				result = append(result, f)
+				flagged = true
+			}
+		}
+		if !flagged && c.isLarge(name) {
+			result = append(result, Finding{
+				Level:   LevelWarning,
+				Rule:    "access-exclusive",
+				Message: fmt.Sprintf("ALTER TABLE acquires an ACCESS EXCLUSIVE lock on %s.", c.describe(name)),
+			})
+		}
+	case *ast.DropStmt:
+		if v.RemoveType != ast.OBJECT_TABLE || v.Objects == nil {
+			break
+		}
+		for _, item := range v.Objects.Items {
+			list, ok := item.(*ast.NodeList)
+			if !ok {
+				continue
+			}
+			name := qualifyNames(toQualifiedName(list))
+			result = append(result, Finding{
+				Level:   LevelError,
+				Rule:    "drop-table",
+				Message: fmt.Sprintf("DROP TABLE permanently deletes %s.", c.describe(name)),
+			})
+			delete(c.sizes, name)
+		}
+	case *ast.RenameStmt:
+		if v.Relation == nil || !c.isExposed(v.Relation) {
+			break
+		}
+		name := qualify(v.Relation)
+		switch v.RenameType {
+		case ast.OBJECT_TABLE, ast.OBJECT_VIEW, ast.OBJECT_MATVIEW:
+			result = append(result, Finding{
+				Level:   LevelWarning,
+				Rule:    "rename",
+				Message: fmt.Sprintf("Renaming %s to %s breaks PostgREST clients using the old name.", name, v.Newname),
+			})
+		case ast.OBJECT_COLUMN:
+			result = append(result, Finding{
+				Level:   LevelWarning,
+				Rule:    "rename",
+				Message: fmt.Sprintf("Renaming column %s.%s to %s breaks PostgREST clients using the old name.", name, v.Subname, v.Newname),
+			})
+		}
+	}
+	return result
+}
+
+func (c *Checker) checkAlterCmd(name string, cmd *ast.AlterTableCmd) (Finding, bool) {
+	switch cmd.Subtype {
+	case ast.AT_AddColumn:
+		def, ok := cmd.Def.(*ast.ColumnDef)
+		if !ok {
+			break
+		}
+		fn, notNull := findVolatileDefault(def)
+		if len(fn) == 0 {
+			break
+		}
+		level, ok := c.lockLevel(name)
+		if !ok {
+			break
+		}
+		column := "column"
+		if notNull {
+			column = "NOT NULL column"
+		}
+		return Finding{
+			Level:   level,
+			Rule:    "volatile-default",
+			Message: fmt.Sprintf("Adding %s %s with volatile default %s() rewrites %s. Add the column without a default and backfill in batches instead.", column, def.Colname, fn, c.describe(name)),
+		}, true
+	case ast.AT_AlterColumnType:
+		if level, ok := c.lockLevel(name); ok {
+			return Finding{
+				Level:   level,
+				Rule:    "alter-column-type",
+				Message: fmt.Sprintf("Changing the type of column %s may rewrite %s.", cmd.Name, c.describe(name)),
+			}, true
+		}
+	case ast.AT_DropColumn:
+		return Finding{
+			Level:   LevelError,
+			Rule:    "drop-column",
+			Message: fmt.Sprintf("DROP COLUMN permanently deletes %s from %s.", cmd.Name, c.describe(name)),
+		}, true
+	}
+	return Finding{}, false
+}
+
+// lockLevel returns the severity of holding a blocking lock on an existing table. Tables
+// unknown to the target database are assumed to be created by pending migrations.
+func (c *Checker) lockLevel(name string) (string, bool) {
+	rows, ok := c.sizes[name]
+	if !ok || rows == 0 {
+		return "", false
+	}
+	if rows == unknownRows {
+		return LevelWarning, true
+	}
+	if rows >= c.largeTableRows {
+		return LevelError, true
+	}
+	return LevelWarning, true
+}
+
+func (c *Checker) isLarge(name string) bool {
+	rows := c.sizes[name]
+	return rows == unknownRows || (rows >= c.largeTableRows && rows > 0)
+}
+
+func (c *Checker) isExposed(rel *ast.RangeVar) bool {
+	schema := rel.SchemaName
+	if len(schema) == 0 {
+		schema = "public"
+	}
+	return slices.Contains(c.exposed, schema)
+}
+
+func (c *Checker) describe(name string) string {
+	if rows, ok := c.sizes[name]; ok && rows > 0 {
+		return fmt.Sprintf("%s (~%d rows)", name, rows)
+	} else if rows == unknownRows {
+		return name + " (size unknown, run ANALYZE to estimate)"
+	}
+	return name
+}
+
+// Functions that are evaluated per row when used as a column default.
+var volatileFuncs = []string{
+	"clock_timestamp",
+	"gen_random_uuid",
+	"nextval",
+	"random",
+	"timeofday",
+	"uuid_generate_v1",
+	"uuid_generate_v1mc",
+	"uuid_generate_v4",
+}
+
+func findVolatileDefault(def *ast.ColumnDef) (fn string, notNull bool) {
+	notNull = def.IsNotNull
+	if def.Constraints == nil {
+		return "", notNull
+	}
+	for _, item := range def.Constraints.Items {
+		con, ok := item.(*ast.Constraint)
+		if !ok {
+			continue
+		}
+		switch con.Contype {
+		case ast.CONSTR_NOTNULL, ast.CONSTR_PRIMARY:
+			notNull = true
+		case ast.CONSTR_DEFAULT:
+			if call, ok := unwrapCast(con.RawExpr).(*ast.FuncCall); ok {
+				names := toQualifiedName(call.Funcname)
+				if len(names) > 0 && slices.Contains(volatileFuncs, strings.ToLower(names[len(names)-1])) {
+					fn = names[len(names)-1]
+				}
+			}
+		}
+	}
+	return fn, notNull
+}
+
+func unwrapCast(n ast.Node) ast.Node {
+	for {
+		cast, ok := n.(*ast.TypeCast)
+		if !ok {
+			return n
+		}
+		n = cast.Arg
+	}
+}
+
+func qualify(rel *ast.RangeVar) string {
+	if rel == nil {
+		return ""
+	}
+	if len(rel.SchemaName) > 0 {
+		return rel.SchemaName + "." + rel.RelName
+	}
+	return "public." + rel.RelName
+}
+
+func qualifyNames(names []string) string {
+	if len(names) == 1 {
+		return "public." + names[0]
+	}
+	return strings.Join(names, ".")
+}
+
+func toQualifiedName(n *ast.NodeList) []string {
+	if n == nil {
+		return nil
+	}
+	var r []string
+	for _, v := range n.Items {
+		if s, ok := v.(*ast.String); ok {
+			r = append(r, s.SVal)
+		}
+	}
+	return r
+}
+
+func makeTable(findings []Finding) string {
+	table := "|Level|Migration|Rule|Message|\n|-|-|-|-|\n"
+	for _, f := range findings {
+		table += fmt.Sprintf("|`%s`|`%s`|`%s`|%s|\n", f.Level, f.Path, f.Rule, strings.ReplaceAll(f.Message, "|", "\\|"))
+	}
+	return table
+}
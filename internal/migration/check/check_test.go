@@ -0,0 +1,193 @@
+package check
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/migration"
+	"github.com/supabase/cli/pkg/pgtest"
+)
+
+var dbConfig = pgconn.Config{
+	Host:     "127.0.0.1",
+	Port:     5432,
+	User:     "admin",
+	Password: "password",
+	Database: "postgres",
+}
+
+func TestCheckFile(t *testing.T) {
+	sizes := func() Sizes {
+		return Sizes{"public.users": 1000000, "public.todos": 10}
+	}
+
+	t.Run("flags blocking index build", func(t *testing.T) {
+		checker := NewChecker(sizes(), 100000, []string{"public"})
+		// Run test
+		findings := checker.CheckFile("0_test.sql", []string{
+			"create index idx_users on users (email)",
+			"create index concurrently idx_users_name on users (name)",
+			"create index idx_todos on public.todos (title)",
+		})
+		// Check error
+		require.Len(t, findings, 2)
+		assert.Equal(t, LevelError, findings[0].Level)
+		assert.Equal(t, "create-index", findings[0].Rule)
+		assert.Equal(t, "0_test.sql", findings[0].Path)
+		assert.Equal(t, LevelWarning, findings[1].Level)
+	})
+
+	t.Run("flags volatile default and type rewrite", func(t *testing.T) {
+		checker := NewChecker(sizes(), 100000, []string{"public"})
+		// Run test
+		findings := checker.CheckFile("0_test.sql", []string{
+			"alter table users add column token uuid not null default gen_random_uuid()",
+			"alter table users add column created_at timestamptz not null default now()",
+			"alter table users alter column id type bigint",
+		})
+		// Check error
+		require.Len(t, findings, 3)
+		assert.Equal(t, "volatile-default", findings[0].Rule)
+		assert.Contains(t, findings[0].Message, "NOT NULL column token")
+		assert.Equal(t, "access-exclusive", findings[1].Rule)
+		assert.Equal(t, "alter-column-type", findings[2].Rule)
+	})
+
+	t.Run("flags destructive statements", func(t *testing.T) {
+		checker := NewChecker(sizes(), 100000, []string{"public"})
+		// Run test
+		findings := checker.CheckFile("0_test.sql", []string{
+			"alter table todos drop column title",
+			"drop table public.users",
+		})
+		// Check error
+		require.Len(t, findings, 2)
+		assert.Equal(t, "drop-column", findings[0].Rule)
+		assert.Equal(t, "drop-table", findings[1].Rule)
+		assert.Equal(t, LevelError, findings[1].Level)
+	})
+
+	t.Run("flags renames in exposed schemas", func(t *testing.T) {
+		checker := NewChecker(sizes(), 100000, []string{"public"})
+		// Run test
+		findings := checker.CheckFile("0_test.sql", []string{
+			"alter table todos rename column title to name",
+			"alter table todos rename to tasks",
+			"alter table private.secrets rename to keys",
+		})
+		// Check error
+		require.Len(t, findings, 2)
+		assert.Equal(t, "rename", findings[0].Rule)
+		assert.Contains(t, findings[0].Message, "public.todos.title")
+		assert.Equal(t, "rename", findings[1].Rule)
+	})
+
+	t.Run("ignores tables created by pending migrations", func(t *testing.T) {
+		checker := NewChecker(sizes(), 100000, []string{"public"})
+		// Run test
+		findings := checker.CheckFile("0_test.sql", []string{
+			"create table posts (id bigint primary key, title text)",
+			"create index idx_posts on posts (title)",
+			"alter table posts alter column title type varchar(255)",
+		})
+		// Check error
+		assert.Empty(t, findings)
+	})
+
+	t.Run("warns on lock of large table", func(t *testing.T) {
+		checker := NewChecker(sizes(), 100000, []string{"public"})
+		// Run test
+		findings := checker.CheckFile("0_test.sql", []string{
+			"alter table users add constraint users_email_key unique (email)",
+		})
+		// Check error
+		require.Len(t, findings, 1)
+		assert.Equal(t, "access-exclusive", findings[0].Rule)
+		assert.Equal(t, LevelWarning, findings[0].Level)
+	})
+
+	t.Run("warns on table never analyzed", func(t *testing.T) {
+		checker := NewChecker(Sizes{"public.events": unknownRows}, 100000, []string{"public"})
+		// Run test
+		findings := checker.CheckFile("0_test.sql", []string{
+			"create index idx_events on events (created_at)",
+			"alter table events add constraint events_pkey primary key (id)",
+		})
+		// Check error
+		require.Len(t, findings, 2)
+		assert.Equal(t, "create-index", findings[0].Rule)
+		assert.Equal(t, LevelWarning, findings[0].Level)
+		assert.Contains(t, findings[0].Message, "size unknown")
+		assert.Equal(t, "access-exclusive", findings[1].Rule)
+	})
+
+	t.Run("checks every statement in a single string", func(t *testing.T) {
+		checker := NewChecker(sizes(), 100000, []string{"public"})
+		// Run test
+		findings := checker.CheckFile("0_test.sql", []string{
+			"alter table todos drop column title; drop table public.users",
+		})
+		// Check error
+		require.Len(t, findings, 2)
+		assert.Equal(t, "drop-column", findings[0].Rule)
+		assert.Equal(t, "drop-table", findings[1].Rule)
+	})
+}
+
+func TestCheckCommand(t *testing.T) {
+	t.Run("fails on error level findings", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		path := filepath.Join(utils.MigrationsDir, "20220727064247_test.sql")
+		require.NoError(t, afero.WriteFile(fsys, path, []byte("drop table users"), 0644))
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 0").
+			Query(LIST_TABLE_SIZES).
+			Reply("SELECT 1", []any{"public", "users", int64(10)})
+		// Run test
+		err := Run(context.Background(), 100000, LevelError, dbConfig, fsys, conn.Intercept)
+		// Check error
+		assert.ErrorContains(t, err, "fail-on is set to error, non-zero exit")
+	})
+
+	t.Run("skips when nothing is pending", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 0")
+		// Run test
+		err := Run(context.Background(), 100000, LevelWarning, dbConfig, fsys, conn.Intercept)
+		// Check error
+		assert.NoError(t, err)
+	})
+
+	t.Run("throws error on query failure", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		path := filepath.Join(utils.MigrationsDir, "20220727064247_test.sql")
+		require.NoError(t, afero.WriteFile(fsys, path, []byte("drop table users"), 0644))
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 0").
+			Query(LIST_TABLE_SIZES).
+			ReplyError("42501", "permission denied for table pg_class")
+		// Run test
+		err := Run(context.Background(), 100000, "none", dbConfig, fsys, conn.Intercept)
+		// Check error
+		assert.ErrorContains(t, err, "permission denied for table pg_class")
+	})
+}
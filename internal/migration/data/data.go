@@ -0,0 +1,294 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+	"github.com/spf13/afero"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/migration"
+)
+
+// BatchDirective configures the key range of a data migration, eg.
+//
+//	-- supabase:batch table=public.users key=id size=10000
+//	update public.users set full_name = first_name || ' ' || last_name where id > $1 and id <= $2;
+const BatchDirective = "-- supabase:batch"
+
+const (
+	DefaultBatchSize = 1000
+
+	CREATE_DATA_TABLE       = "CREATE TABLE IF NOT EXISTS supabase_migrations.data_migrations (version text NOT NULL PRIMARY KEY, name text, hash text NOT NULL, last_key bigint NOT NULL, affected bigint NOT NULL, completed_at timestamptz)"
+	SELECT_DATA_PROGRESS    = "SELECT hash, last_key, affected, completed_at IS NOT NULL FROM supabase_migrations.data_migrations WHERE version = $1"
+	UPSERT_DATA_PROGRESS    = "INSERT INTO supabase_migrations.data_migrations(version, name, hash, last_key, affected) VALUES($1, $2, $3, $4, $5) ON CONFLICT (version) DO UPDATE SET hash = EXCLUDED.hash, last_key = EXCLUDED.last_key, affected = EXCLUDED.affected"
+	COMPLETE_DATA_MIGRATION = "UPDATE supabase_migrations.data_migrations SET completed_at = now() WHERE version = $1"
+	DELETE_DATA_PROGRESS    = "DELETE FROM supabase_migrations.data_migrations WHERE version = $1"
+)
+
+var (
+	DataMigrationsDir = filepath.Join(utils.MigrationsDir, "data")
+
+	ErrMissingDirective = errors.New("Data migration must start with " + BatchDirective + " table=<table> key=<column>")
+	ErrDataChanged      = errors.New("Statement of a partially applied data migration has changed.")
+)
+
+// Batch is the key range configuration of a data migration.
+type Batch struct {
+	Table string
+	Key   string
+	Size  int64
+}
+
+func ParseBatch(m *migration.MigrationFile) (Batch, error) {
+	result := Batch{Size: DefaultBatchSize}
+	if len(m.Statements) != 1 {
+		return result, errors.Errorf("Data migration must contain exactly 1 statement: found %d", len(m.Statements))
+	}
+	for _, line := range strings.Split(m.Statements[0], "\n") {
+		args, ok := strings.CutPrefix(strings.TrimSpace(line), BatchDirective)
+		if !ok {
+			continue
+		}
+		for _, kv := range strings.Fields(args) {
+			k, v, _ := strings.Cut(kv, "=")
+			switch k {
+			case "table":
+				result.Table = v
+			case "key":
+				result.Key = v
+			case "size":
+				size, err := strconv.ParseInt(v, 10, 64)
+				if err != nil || size <= 0 {
+					return result, errors.Errorf("invalid batch size: %s", v)
+				}
+				result.Size = size
+			default:
+				return result, errors.Errorf("unknown batch option: %s", kv)
+			}
+		}
+		break
+	}
+	if len(result.Table) == 0 || len(result.Key) == 0 {
+		return result, errors.New(ErrMissingDirective)
+	}
+	return result, nil
+}
+
+const dataTemplate = BatchDirective + ` table=public.my_table key=id size=1000
+-- Applied repeatedly with the key range ($1, $2] until a batch beyond the largest key affects no rows.
+update public.my_table set updated_at = now() where id > $1 and id <= $2;
+`
+
+// New creates a data migration file from template.
+func New(name string, fsys afero.Fs) error {
+	path := filepath.Join(DataMigrationsDir, fmt.Sprintf("%s_%s.sql", utils.GetCurrentTimestamp(), name))
+	if err := utils.WriteFile(path, []byte(dataTemplate), fsys); err != nil {
+		return err
+	}
+	fmt.Println("Created new data migration at " + utils.Bold(path))
+	return nil
+}
+
+func Run(ctx context.Context, config pgconn.Config, fsys afero.Fs, options ...func(*pgx.ConnConfig)) error {
+	pending, err := migration.ListLocalMigrations(DataMigrationsDir, afero.NewIOFS(fsys))
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		fmt.Fprintln(os.Stderr, "No data migrations found.")
+		return nil
+	}
+	conn, err := utils.ConnectByConfig(ctx, config, options...)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+	if err := createDataTable(ctx, conn); err != nil {
+		return err
+	}
+	for _, path := range pending {
+		m, err := migration.NewMigrationFromFile(path, afero.NewIOFS(fsys))
+		if err != nil {
+			return err
+		}
+		if err := applyDataMigration(ctx, m, conn, os.Stderr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createDataTable(ctx context.Context, conn *pgx.Conn) error {
+	batch := pgconn.Batch{}
+	batch.ExecParams(migration.SET_LOCK_TIMEOUT, nil, nil, nil, nil)
+	batch.ExecParams(migration.CREATE_VERSION_SCHEMA, nil, nil, nil, nil)
+	batch.ExecParams(CREATE_DATA_TABLE, nil, nil, nil, nil)
+	if _, err := conn.PgConn().ExecBatch(ctx, &batch).ReadAll(); err != nil {
+		return errors.Errorf("failed to create data migration table: %w", err)
+	}
+	return nil
+}
+
+type progress struct {
+	found     bool
+	lastKey   int64
+	affected  int64
+	completed bool
+}
+
+func applyDataMigration(ctx context.Context, m *migration.MigrationFile, conn *pgx.Conn, w io.Writer) error {
+	filename := m.Version + "_" + m.Name + ".sql"
+	cfg, err := ParseBatch(m)
+	if err != nil {
+		return errors.Errorf("failed to parse %s: %w", filename, err)
+	}
+	state, err := readProgress(ctx, m, conn)
+	if err != nil {
+		return err
+	} else if state.completed {
+		fmt.Fprintf(w, "Skipping completed data migration %s...\n", filename)
+		return nil
+	}
+	// Bounds are only used for estimating progress; batches continue until no rows are affected
+	var minKey, maxKey pgtype.Int8
+	if err := conn.QueryRow(ctx, fmt.Sprintf("SELECT min(%[1]s)::bigint, max(%[1]s)::bigint FROM %[2]s", cfg.Key, cfg.Table)).Scan(&minKey, &maxKey); err != nil {
+		return errors.Errorf("failed to read key range: %w", err)
+	}
+	if !state.found && minKey.Status == pgtype.Present {
+		state.lastKey = minKey.Int - 1
+	}
+	if state.found {
+		fmt.Fprintf(w, "Resuming data migration %s from key %d...\n", filename, state.lastKey)
+	} else {
+		fmt.Fprintf(w, "Applying data migration %s...\n", filename)
+	}
+	meter := newMeter(state.lastKey, maxKey, w, time.Now)
+	for {
+		upper := state.lastKey + cfg.Size
+		affected, err := applyBatch(ctx, m, state, upper, conn)
+		if err != nil {
+			return errors.Errorf("failed to apply batch (%d, %d]: %w\nRerun the command to resume from key %d.", state.lastKey, upper, err, state.lastKey)
+		}
+		// Stop at the first empty batch beyond the initial key range
+		done := affected == 0 && (maxKey.Status != pgtype.Present || upper >= maxKey.Int)
+		state.lastKey, state.affected = upper, state.affected+affected
+		meter.report(state.lastKey, affected, done)
+		if done {
+			break
+		}
+	}
+	if _, err := conn.Exec(ctx, COMPLETE_DATA_MIGRATION, m.Version); err != nil {
+		return errors.Errorf("failed to complete data migration: %w", err)
+	}
+	fmt.Fprintf(w, "Finished data migration %s: %d rows affected.\n", filename, state.affected)
+	return nil
+}
+
+func readProgress(ctx context.Context, m *migration.MigrationFile, conn *pgx.Conn) (progress, error) {
+	var result progress
+	var hash string
+	if err := conn.QueryRow(ctx, SELECT_DATA_PROGRESS, m.Version).Scan(&hash, &result.lastKey, &result.affected, &result.completed); errors.Is(err, pgx.ErrNoRows) {
+		return result, nil
+	} else if err != nil {
+		return result, errors.Errorf("failed to read data migration progress: %w", err)
+	}
+	result.found = true
+	if !result.completed && hash != m.Checksum() {
+		return result, errors.Errorf("%w\nTo restart data migration %s from the beginning, run:\n%s", ErrDataChanged, m.Version,
+			strings.Replace(DELETE_DATA_PROGRESS, "$1", "'"+m.Version+"'", 1))
+	}
+	return result, nil
+}
+
+// applyBatch commits the rows affected by a batch together with its progress.
+func applyBatch(ctx context.Context, m *migration.MigrationFile, state progress, upper int64, conn *pgx.Conn) (int64, error) {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return 0, errors.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		// Rollback is a no-op after commit
+		_ = tx.Rollback(context.Background())
+	}()
+	tag, err := conn.PgConn().ExecParams(ctx, m.Statements[0],
+		[][]byte{encodeInt(state.lastKey), encodeInt(upper)},
+		[]uint32{pgtype.Int8OID, pgtype.Int8OID},
+		[]int16{pgtype.TextFormatCode, pgtype.TextFormatCode},
+		nil,
+	).Close()
+	if err != nil {
+		return 0, err
+	}
+	affected := tag.RowsAffected()
+	if _, err := conn.PgConn().ExecParams(ctx, UPSERT_DATA_PROGRESS,
+		[][]byte{[]byte(m.Version), []byte(m.Name), []byte(m.Checksum()), encodeInt(upper), encodeInt(state.affected + affected)},
+		[]uint32{pgtype.TextOID, pgtype.TextOID, pgtype.TextOID, pgtype.Int8OID, pgtype.Int8OID},
+		[]int16{pgtype.TextFormatCode, pgtype.TextFormatCode, pgtype.TextFormatCode, pgtype.TextFormatCode, pgtype.TextFormatCode},
+		nil,
+	).Close(); err != nil {
+		return 0, errors.Errorf("failed to update data migration progress: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, errors.Errorf("failed to commit batch: %w", err)
+	}
+	return affected, nil
+}
+
+func encodeInt(v int64) []byte {
+	return []byte(strconv.FormatInt(v, 10))
+}
+
+// meter reports throughput and estimated time remaining at most once per interval.
+type meter struct {
+	w        io.Writer
+	now      func() time.Time
+	start    time.Time
+	last     time.Time
+	startKey int64
+	maxKey   pgtype.Int8
+	rows     int64
+}
+
+const reportInterval = 5 * time.Second
+
+func newMeter(startKey int64, maxKey pgtype.Int8, w io.Writer, now func() time.Time) *meter {
+	start := now()
+	return &meter{
+		w:        w,
+		now:      now,
+		start:    start,
+		last:     start,
+		startKey: startKey,
+		maxKey:   maxKey,
+	}
+}
+
+func (m *meter) report(key, affected int64, done bool) {
+	m.rows += affected
+	now := m.now()
+	if !done && now.Sub(m.last) < reportInterval {
+		return
+	}
+	m.last = now
+	elapsed := now.Sub(m.start).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	line := fmt.Sprintf("Processed %d rows up to key %d (%.0f rows/s)", m.rows, key, float64(m.rows)/elapsed)
+	if !done && m.maxKey.Status == pgtype.Present && key > m.startKey && key < m.maxKey.Int {
+		keysPerSecond := float64(key-m.startKey) / elapsed
+		eta := time.Duration(float64(m.maxKey.Int-key) / keysPerSecond * float64(time.Second))
+		line += ", ETA " + eta.Round(time.Second).String()
+	}
+	fmt.Fprintln(m.w, line)
+}
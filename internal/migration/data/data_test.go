@@ -0,0 +1,231 @@
+package data
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgtype"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/cli/pkg/migration"
+	"github.com/supabase/cli/pkg/pgtest"
+)
+
+var dbConfig = pgconn.Config{
+	Host:     "127.0.0.1",
+	Port:     5432,
+	User:     "admin",
+	Password: "password",
+	Database: "postgres",
+}
+
+const (
+	testStatement = BatchDirective + " table=public.users key=id size=1000\nupdate public.users set active = true where id > $1 and id <= $2"
+	testKeyRange  = "SELECT min(id)::bigint, max(id)::bigint FROM public.users"
+)
+
+func TestParseBatch(t *testing.T) {
+	t.Run("parses batch directive", func(t *testing.T) {
+		m := migration.MigrationFile{Statements: []string{"-- backfill\n" + testStatement}}
+		// Run test
+		batch, err := ParseBatch(&m)
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, Batch{Table: "public.users", Key: "id", Size: 1000}, batch)
+	})
+
+	t.Run("throws error on missing directive", func(t *testing.T) {
+		m := migration.MigrationFile{Statements: []string{"update users set active = true"}}
+		// Run test
+		_, err := ParseBatch(&m)
+		// Check error
+		assert.ErrorIs(t, err, ErrMissingDirective)
+	})
+
+	t.Run("throws error on invalid size", func(t *testing.T) {
+		m := migration.MigrationFile{Statements: []string{BatchDirective + " table=users key=id size=0\nselect 1"}}
+		// Run test
+		_, err := ParseBatch(&m)
+		// Check error
+		assert.ErrorContains(t, err, "invalid batch size: 0")
+	})
+
+	t.Run("throws error on multiple statements", func(t *testing.T) {
+		m := migration.MigrationFile{Statements: []string{testStatement, "select 1"}}
+		// Run test
+		_, err := ParseBatch(&m)
+		// Check error
+		assert.ErrorContains(t, err, "exactly 1 statement: found 2")
+	})
+}
+
+func TestRunData(t *testing.T) {
+	path := filepath.Join(DataMigrationsDir, "20220727064247_backfill.sql")
+	file := migration.MigrationFile{
+		Version:    "20220727064247",
+		Name:       "backfill",
+		Statements: []string{testStatement},
+	}
+
+	mockBatch := func(conn *pgtest.MockConn, lower, upper, total string, tag string) {
+		conn.Query("begin").
+			Reply("BEGIN").
+			Query(testStatement, lower, upper).
+			Reply(tag).
+			Query(UPSERT_DATA_PROGRESS, file.Version, file.Name, file.Checksum(), upper, total).
+			Reply("INSERT 0 1").
+			Query("commit").
+			Reply("COMMIT")
+	}
+
+	t.Run("applies batches until no rows are affected", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fsys, path, []byte(testStatement+";"), 0644))
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.SET_LOCK_TIMEOUT).
+			Query(migration.CREATE_VERSION_SCHEMA).
+			Reply("CREATE SCHEMA").
+			Query(CREATE_DATA_TABLE).
+			Reply("CREATE TABLE").
+			Query(SELECT_DATA_PROGRESS, file.Version).
+			Reply("SELECT 0").
+			Query(testKeyRange).
+			Reply("SELECT 1", []any{int64(1), int64(1500)})
+		mockBatch(conn, "0", "1000", "1000", "UPDATE 1000")
+		mockBatch(conn, "1000", "2000", "1500", "UPDATE 500")
+		mockBatch(conn, "2000", "3000", "1500", "UPDATE 0")
+		conn.Query(COMPLETE_DATA_MIGRATION, file.Version).
+			Reply("UPDATE 1")
+		// Run test
+		err := Run(context.Background(), dbConfig, fsys, conn.Intercept)
+		// Check error
+		assert.NoError(t, err)
+	})
+
+	t.Run("resumes from last key", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fsys, path, []byte(testStatement+";"), 0644))
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.SET_LOCK_TIMEOUT).
+			Query(migration.CREATE_VERSION_SCHEMA).
+			Reply("CREATE SCHEMA").
+			Query(CREATE_DATA_TABLE).
+			Reply("CREATE TABLE").
+			Query(SELECT_DATA_PROGRESS, file.Version).
+			Reply("SELECT 1", []any{file.Checksum(), int64(1000), int64(1000), false}).
+			Query(testKeyRange).
+			Reply("SELECT 1", []any{int64(1), int64(1500)})
+		mockBatch(conn, "1000", "2000", "1500", "UPDATE 500")
+		mockBatch(conn, "2000", "3000", "1500", "UPDATE 0")
+		conn.Query(COMPLETE_DATA_MIGRATION, file.Version).
+			Reply("UPDATE 1")
+		// Run test
+		err := Run(context.Background(), dbConfig, fsys, conn.Intercept)
+		// Check error
+		assert.NoError(t, err)
+	})
+
+	t.Run("skips completed migration", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fsys, path, []byte(testStatement+";"), 0644))
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.SET_LOCK_TIMEOUT).
+			Query(migration.CREATE_VERSION_SCHEMA).
+			Reply("CREATE SCHEMA").
+			Query(CREATE_DATA_TABLE).
+			Reply("CREATE TABLE").
+			Query(SELECT_DATA_PROGRESS, file.Version).
+			Reply("SELECT 1", []any{"", int64(2000), int64(1500), true})
+		// Run test
+		err := Run(context.Background(), dbConfig, fsys, conn.Intercept)
+		// Check error
+		assert.NoError(t, err)
+	})
+
+	t.Run("throws error on changed statement", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fsys, path, []byte(testStatement+";"), 0644))
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.SET_LOCK_TIMEOUT).
+			Query(migration.CREATE_VERSION_SCHEMA).
+			Reply("CREATE SCHEMA").
+			Query(CREATE_DATA_TABLE).
+			Reply("CREATE TABLE").
+			Query(SELECT_DATA_PROGRESS, file.Version).
+			Reply("SELECT 1", []any{"hash", int64(1000), int64(1000), false})
+		// Run test
+		err := Run(context.Background(), dbConfig, fsys, conn.Intercept)
+		// Check error
+		assert.ErrorIs(t, err, ErrDataChanged)
+	})
+
+	t.Run("throws error on batch failure", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fsys, path, []byte(testStatement+";"), 0644))
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.SET_LOCK_TIMEOUT).
+			Query(migration.CREATE_VERSION_SCHEMA).
+			Reply("CREATE SCHEMA").
+			Query(CREATE_DATA_TABLE).
+			Reply("CREATE TABLE").
+			Query(SELECT_DATA_PROGRESS, file.Version).
+			Reply("SELECT 0").
+			Query(testKeyRange).
+			Reply("SELECT 1", []any{int64(1), int64(1500)}).
+			Query("begin").
+			Reply("BEGIN").
+			Query(testStatement, "0", "1000").
+			ReplyError(pgerrcode.QueryCanceled, "canceling statement due to statement timeout").
+			Query("rollback").
+			Reply("ROLLBACK")
+		// Run test
+		err := Run(context.Background(), dbConfig, fsys, conn.Intercept)
+		// Check error
+		assert.ErrorContains(t, err, "canceling statement due to statement timeout")
+		assert.ErrorContains(t, err, "Rerun the command to resume from key 0.")
+	})
+
+	t.Run("skips empty directory", func(t *testing.T) {
+		// Run test
+		err := Run(context.Background(), dbConfig, afero.NewMemMapFs())
+		// Check error
+		assert.NoError(t, err)
+	})
+}
+
+func TestMeter(t *testing.T) {
+	var out bytes.Buffer
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	m := newMeter(0, pgtype.Int8{Int: 4000, Status: pgtype.Present}, &out, clock)
+	// Run test
+	now = now.Add(time.Second)
+	m.report(1000, 1000, false)
+	now = now.Add(4 * time.Second)
+	m.report(2000, 1000, false)
+	now = now.Add(5 * time.Second)
+	m.report(4000, 0, true)
+	// Check output
+	assert.Equal(t, "Processed 2000 rows up to key 2000 (400 rows/s), ETA 5s\nProcessed 2000 rows up to key 4000 (200 rows/s)\n", out.String())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/go-errors/errors"
 	"github.com/jackc/pgconn"
@@ -33,7 +34,20 @@ func Run(ctx context.Context, last uint, config pgconn.Config, fsys afero.Fs, op
 		utils.CmdSuggestion = fmt.Sprintf("Try %s if you want to revert all migrations.", utils.Aqua("supabase db reset"))
 		return errors.Errorf("--last must be smaller than total applied migrations: %d", total)
 	}
-	msg := confirmResetAll(remoteMigrations[total-last:])
+	pending := remoteMigrations[total-last:]
+	scripts, err := migration.ReadRollbackScripts(ctx, pending, conn)
+	if err != nil {
+		return err
+	}
+	missing := migration.FindMissingRollbacks(pending, scripts)
+	if len(missing) == 0 {
+		return RollbackAll(ctx, scripts, conn)
+	} else if len(missing) < len(pending) {
+		// Resetting would lose data that the available down scripts are meant to preserve
+		utils.CmdSuggestion = fmt.Sprintf("Add the missing down scripts, or run %s to reset instead.", utils.Aqua(fmt.Sprintf("supabase db reset --last %d", last)))
+		return errors.Errorf("%w: %s", migration.ErrMissingRollback, strings.Join(missing, ", "))
+	}
+	msg := confirmResetAll(pending)
 	if shouldReset, err := utils.NewConsole().PromptYesNo(ctx, msg, false); err != nil {
 		return err
 	} else if !shouldReset {
@@ -44,6 +58,21 @@ func Run(ctx context.Context, last uint, config pgconn.Config, fsys afero.Fs, op
 	return ResetAll(ctx, version, conn, fsys)
 }
 
+// RollbackAll runs the recorded down scripts of each migration in reverse order.
+func RollbackAll(ctx context.Context, scripts []migration.MigrationFile, conn *pgx.Conn) error {
+	msg := confirmRollback(scripts)
+	if shouldRevert, err := utils.NewConsole().PromptYesNo(ctx, msg, false); err != nil {
+		return err
+	} else if !shouldRevert {
+		return errors.New(context.Canceled)
+	}
+	if err := migration.RollbackMigrations(ctx, scripts, conn); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "Reverted", len(scripts), "migrations.")
+	return nil
+}
+
 func ResetAll(ctx context.Context, version string, conn *pgx.Conn, fsys afero.Fs) error {
 	if err := migration.DropUserSchemas(ctx, conn); err != nil {
 		return err
@@ -62,3 +91,11 @@ func confirmResetAll(pending []string) string {
 	msg += fmt.Sprintf("%s you will lose all data in this database.", utils.Yellow("WARNING:"))
 	return msg
 }
+
+func confirmRollback(scripts []migration.MigrationFile) string {
+	msg := fmt.Sprintln("Do you want to revert the following migrations using their down scripts?")
+	for _, m := range scripts {
+		msg += fmt.Sprintf(" • %s\n", utils.Bold(m.Version))
+	}
+	return strings.TrimSuffix(msg, "\n")
+}
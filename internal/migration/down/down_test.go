@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/supabase/cli/internal/testing/fstest"
 	"github.com/supabase/cli/internal/testing/helper"
 	"github.com/supabase/cli/internal/utils"
 	"github.com/supabase/cli/pkg/migration"
@@ -40,7 +41,9 @@ func TestMigrationsDown(t *testing.T) {
 		conn := pgtest.NewConn()
 		defer conn.Close(t)
 		conn.Query(migration.LIST_MIGRATION_VERSION).
-			Reply("SELECT 2", []any{"20221201000000"}, []any{"20221201000001"})
+			Reply("SELECT 2", []any{"20221201000000"}, []any{"20221201000001"}).
+			Query(migration.SELECT_MIGRATION_ROLLBACK, []string{"20221201000001"}).
+			Reply("SELECT 0")
 		// Run test
 		err := Run(context.Background(), 1, dbConfig, fsys, conn.Intercept)
 		// Check error
@@ -76,6 +79,106 @@ func TestMigrationsDown(t *testing.T) {
 	})
 }
 
+func TestRollbackMigrations(t *testing.T) {
+	t.Run("reverts last n migrations", func(t *testing.T) {
+		t.Cleanup(fstest.MockStdin(t, "y"))
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 3", []any{"20221201000000"}, []any{"20221201000001"}, []any{"20221201000002"}).
+			Query(migration.SELECT_MIGRATION_ROLLBACK, []string{"20221201000001", "20221201000002"}).
+			Reply("SELECT 2",
+				[]any{"20221201000002", "third", []string{"drop table c"}},
+				[]any{"20221201000001", "second", []string{"drop table b"}},
+			).
+			Query("drop table c").
+			Reply("DROP TABLE").
+			Query("drop table b").
+			Reply("DROP TABLE").
+			Query(migration.DELETE_MIGRATION_VERSION, []string{"20221201000002", "20221201000001"}).
+			Reply("DELETE 2")
+		// Run test
+		err := Run(context.Background(), 2, dbConfig, fsys, conn.Intercept)
+		// Check error
+		assert.NoError(t, err)
+	})
+
+	t.Run("reverts down scripts outside transaction", func(t *testing.T) {
+		t.Cleanup(fstest.MockStdin(t, "y"))
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		dropIndex := migration.NoTransactionDirective + "\ndrop index concurrently idx_c"
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 3", []any{"20221201000000"}, []any{"20221201000001"}, []any{"20221201000002"}).
+			Query(migration.SELECT_MIGRATION_ROLLBACK, []string{"20221201000001", "20221201000002"}).
+			Reply("SELECT 2",
+				[]any{"20221201000002", "third", []string{dropIndex, "drop table c"}},
+				[]any{"20221201000001", "second", []string{"drop table b"}},
+			).
+			Query(dropIndex).
+			Reply("DROP INDEX").
+			Query("drop table c").
+			Reply("DROP TABLE").
+			Query(migration.DELETE_MIGRATION_VERSION, []string{"20221201000002"}).
+			Reply("DELETE 1").
+			Query("drop table b").
+			Reply("DROP TABLE").
+			Query(migration.DELETE_MIGRATION_VERSION, []string{"20221201000001"}).
+			Reply("DELETE 1")
+		// Run test
+		err := Run(context.Background(), 2, dbConfig, fsys, conn.Intercept)
+		// Check error
+		assert.NoError(t, err)
+	})
+
+	t.Run("throws error on rollback failure", func(t *testing.T) {
+		t.Cleanup(fstest.MockStdin(t, "y"))
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 2", []any{"20221201000000"}, []any{"20221201000001"}).
+			Query(migration.SELECT_MIGRATION_ROLLBACK, []string{"20221201000001"}).
+			Reply("SELECT 1", []any{"20221201000001", "second", []string{"drop table b"}}).
+			Query("drop table b").
+			ReplyError(pgerrcode.UndefinedTable, `relation "b" does not exist`).
+			Query(migration.DELETE_MIGRATION_VERSION, []string{"20221201000001"})
+		// Run test
+		err := Run(context.Background(), 1, dbConfig, fsys, conn.Intercept)
+		// Check error
+		assert.ErrorContains(t, err, `ERROR: relation "b" does not exist (SQLSTATE 42P01)`)
+		assert.ErrorContains(t, err, "At statement: 0\ndrop table b")
+	})
+
+	t.Run("throws error on missing down script", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 3", []any{"20221201000000"}, []any{"20221201000001"}, []any{"20221201000002"}).
+			Query(migration.SELECT_MIGRATION_ROLLBACK, []string{"20221201000001", "20221201000002"}).
+			Reply("SELECT 2",
+				[]any{"20221201000002", "third", []string{"drop table c"}},
+				[]any{"20221201000001", "second", []string{}},
+			)
+		// Run test
+		err := Run(context.Background(), 2, dbConfig, fsys, conn.Intercept)
+		// Check error
+		assert.ErrorIs(t, err, migration.ErrMissingRollback)
+		assert.ErrorContains(t, err, "20221201000001")
+	})
+}
+
 func TestResetRemote(t *testing.T) {
 	t.Run("resets remote database", func(t *testing.T) {
 		// Setup in-memory fs
@@ -90,7 +193,7 @@ func TestResetRemote(t *testing.T) {
 		helper.MockMigrationHistory(conn).
 			Query("RESET ALL").
 			Reply("RESET").
-			Query(migration.INSERT_MIGRATION_VERSION, "0", "schema", nil).
+			Query(migration.INSERT_MIGRATION_VERSION, "0", "schema", nil, helper.MigrationHash()).
 			Reply("INSERT 0 1")
 		// Run test
 		err := ResetAll(context.Background(), "", conn.MockClient(t), fsys)
@@ -114,7 +217,7 @@ func TestResetRemote(t *testing.T) {
 		helper.MockMigrationHistory(conn).
 			Query("RESET ALL").
 			Reply("RESET").
-			Query(migration.INSERT_MIGRATION_VERSION, "0", "schema", nil).
+			Query(migration.INSERT_MIGRATION_VERSION, "0", "schema", nil, helper.MigrationHash()).
 			Reply("INSERT 0 1")
 		utils.Config.Db.Seed.Enabled = false
 		// Run test
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"os"
 	"strconv"
 
 	"github.com/jackc/pgconn"
@@ -14,10 +15,51 @@ import (
 )
 
 func Run(ctx context.Context, config pgconn.Config, fsys afero.Fs, options ...func(*pgx.ConnConfig)) error {
-	remoteVersions, err := loadRemoteVersions(ctx, config, options...)
+	remoteVersions, hashes, err := loadRemoteVersions(ctx, config, options...)
 	if err != nil {
 		return err
 	}
+	if err := renderTable(remoteVersions, fsys); err != nil {
+		return err
+	}
+	_, err = CheckDrift(remoteVersions, hashes, fsys)
+	return err
+}
+
+func RunViaApi(ctx context.Context, projectRef string, fsys afero.Fs) error {
+	history := migration.NewApiHistory(projectRef, *utils.GetSupabase())
+	remoteVersions, err := history.ListRemoteMigrations(ctx)
+	if err != nil {
+		return err
+	}
+	if err := renderTable(remoteVersions, fsys); err != nil {
+		return err
+	}
+	// Checksums are not exposed by the Management API
+	_, err = CheckDrift(remoteVersions, nil, fsys)
+	return err
+}
+
+// CheckDrift warns about local migration files that have drifted from the remote migration history.
+func CheckDrift(remoteVersions []string, hashes map[string]string, fsys afero.Fs) ([]migration.Drift, error) {
+	localMigrations, err := migration.ListLocalMigrations(utils.MigrationsDir, afero.NewIOFS(fsys))
+	if err != nil {
+		return nil, err
+	}
+	drift, err := migration.FindDrift(localMigrations, remoteVersions, hashes, afero.NewIOFS(fsys))
+	if err != nil {
+		return nil, err
+	}
+	if len(drift) > 0 {
+		fmt.Fprintln(os.Stderr, utils.Yellow("WARNING:"), "Local migration files have drifted from the remote migration history:")
+		for _, d := range drift {
+			fmt.Fprintf(os.Stderr, " • %s %s\n", utils.Bold(d.Version), d.Kind)
+		}
+	}
+	return drift, nil
+}
+
+func renderTable(remoteVersions []string, fsys afero.Fs) error {
 	localVersions, err := LoadLocalVersions(fsys)
 	if err != nil {
 		return err
@@ -26,13 +68,18 @@ func Run(ctx context.Context, config pgconn.Config, fsys afero.Fs, options ...fu
 	return utils.RenderTable(table)
 }
 
-func loadRemoteVersions(ctx context.Context, config pgconn.Config, options ...func(*pgx.ConnConfig)) ([]string, error) {
+func loadRemoteVersions(ctx context.Context, config pgconn.Config, options ...func(*pgx.ConnConfig)) ([]string, map[string]string, error) {
 	conn, err := utils.ConnectByConfig(ctx, config, options...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer conn.Close(context.Background())
-	return migration.ListRemoteMigrations(ctx, conn)
+	versions, err := migration.ListRemoteMigrations(ctx, conn)
+	if err != nil || len(versions) == 0 {
+		return versions, nil, err
+	}
+	hashes, err := migration.ReadRemoteHashes(ctx, conn)
+	return versions, hashes, err
 }
 
 func makeTable(remoteMigrations, localMigrations []string) string {
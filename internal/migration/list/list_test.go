@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/supabase/cli/internal/testing/fstest"
+	"github.com/supabase/cli/internal/testing/helper"
 	"github.com/supabase/cli/internal/utils"
 	"github.com/supabase/cli/pkg/migration"
 	"github.com/supabase/cli/pkg/pgtest"
@@ -41,6 +42,27 @@ func TestMigrationList(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("warns on modified migrations", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		path := filepath.Join(utils.MigrationsDir, "20220727064247_test.sql")
+		require.NoError(t, afero.WriteFile(fsys, path, []byte("select 2"), 0644))
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.LIST_MIGRATION_VERSION).
+			Reply("SELECT 1", []any{"20220727064247"}).
+			Query(migration.SELECT_MIGRATION_HASH).
+			Reply("SELECT 1", []any{"20220727064247", helper.MigrationHash("select 1")})
+		// Run test
+		remote, hashes, err := loadRemoteVersions(context.Background(), dbConfig, conn.Intercept)
+		require.NoError(t, err)
+		drift, err := CheckDrift(remote, hashes, fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, []migration.Drift{{Version: "20220727064247", Kind: migration.DriftModified}}, drift)
+	})
+
 	t.Run("throws error on remote failure", func(t *testing.T) {
 		// Setup in-memory fs
 		fsys := afero.NewMemMapFs()
@@ -71,17 +93,20 @@ func TestRemoteMigrations(t *testing.T) {
 		conn := pgtest.NewConn()
 		defer conn.Close(t)
 		conn.Query(migration.LIST_MIGRATION_VERSION).
-			Reply("SELECT 1", []any{"20220727064247"})
+			Reply("SELECT 1", []any{"20220727064247"}).
+			Query(migration.SELECT_MIGRATION_HASH).
+			Reply("SELECT 1", []any{"20220727064247", "abc"})
 		// Run test
-		versions, err := loadRemoteVersions(context.Background(), dbConfig, conn.Intercept)
+		versions, hashes, err := loadRemoteVersions(context.Background(), dbConfig, conn.Intercept)
 		// Check error
 		assert.NoError(t, err)
 		assert.ElementsMatch(t, []string{"20220727064247"}, versions)
+		assert.Equal(t, map[string]string{"20220727064247": "abc"}, hashes)
 	})
 
 	t.Run("throws error on connect failure", func(t *testing.T) {
 		// Run test
-		_, err := loadRemoteVersions(context.Background(), pgconn.Config{})
+		_, _, err := loadRemoteVersions(context.Background(), pgconn.Config{})
 		// Check error
 		assert.ErrorContains(t, err, "invalid port (outside range)")
 	})
@@ -93,7 +118,7 @@ func TestRemoteMigrations(t *testing.T) {
 		conn.Query(migration.LIST_MIGRATION_VERSION).
 			ReplyError(pgerrcode.UndefinedTable, "relation \"supabase_migrations.schema_migrations\" does not exist")
 		// Run test
-		versions, err := loadRemoteVersions(context.Background(), dbConfig, conn.Intercept)
+		versions, _, err := loadRemoteVersions(context.Background(), dbConfig, conn.Intercept)
 		// Check error
 		assert.NoError(t, err)
 		assert.Empty(t, versions)
@@ -106,7 +131,7 @@ func TestRemoteMigrations(t *testing.T) {
 		conn.Query(migration.LIST_MIGRATION_VERSION).
 			Reply("SELECT 1", []any{})
 		// Run test
-		_, err := loadRemoteVersions(context.Background(), dbConfig, conn.Intercept)
+		_, _, err := loadRemoteVersions(context.Background(), dbConfig, conn.Intercept)
 		// Check error
 		assert.ErrorContains(t, err, "number of field descriptions must equal number of destinations, got 0 and 1")
 	})
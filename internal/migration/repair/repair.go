@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 
 	"github.com/go-errors/errors"
@@ -24,35 +25,60 @@ const (
 var ErrInvalidVersion = errors.New("invalid version number")
 
 func Run(ctx context.Context, config pgconn.Config, version []string, status string, fsys afero.Fs, options ...func(*pgx.ConnConfig)) error {
+	version, repairAll, err := resolveVersions(ctx, version, fsys)
+	if err != nil {
+		return err
+	}
+	conn, err := utils.ConnectByConfig(ctx, config, options...)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+	// Update migration history
+	if err = UpdateMigrationTable(ctx, conn, version, status, repairAll, fsys); err == nil {
+		utils.CmdSuggestion = fmt.Sprintf("Run %s to show the updated migration history.", utils.Aqua("supabase migration list"))
+	}
+	return err
+}
+
+func RunViaApi(ctx context.Context, projectRef string, version []string, status string, fsys afero.Fs) error {
+	if status == Reverted {
+		utils.CmdSuggestion = fmt.Sprintf("Run %s without --via-api to edit the history table directly.", utils.Aqua("supabase migration repair --status reverted"))
+		return errors.New(migration.ErrRevertViaApi)
+	}
+	version, repairAll, err := resolveVersions(ctx, version, fsys)
+	if err != nil {
+		return err
+	}
+	// Update migration history
+	history := migration.NewApiHistory(projectRef, *utils.GetSupabase())
+	if err = updateMigrationHistory(ctx, history, version, status, repairAll, fsys); err == nil {
+		utils.CmdSuggestion = fmt.Sprintf("Run %s to show the updated migration history.", utils.Aqua("supabase migration list --via-api"))
+	}
+	return err
+}
+
+func resolveVersions(ctx context.Context, version []string, fsys afero.Fs) ([]string, bool, error) {
 	for _, v := range version {
 		if _, err := strconv.Atoi(v); err != nil {
-			return errors.Errorf("failed to parse %s: %w", v, ErrInvalidVersion)
+			return nil, false, errors.Errorf("failed to parse %s: %w", v, ErrInvalidVersion)
 		}
 	}
 	repairAll := len(version) == 0
 	if repairAll {
 		msg := "Do you want to repair the entire migration history table to match local migration files?"
 		if shouldRepair, err := utils.NewConsole().PromptYesNo(ctx, msg, false); err != nil {
-			return err
+			return nil, false, err
 		} else if !shouldRepair {
-			return errors.New(context.Canceled)
+			return nil, false, errors.New(context.Canceled)
 		}
 		local, err := list.LoadLocalVersions(fsys)
 		if err != nil {
-			return err
+			return nil, false, err
 		}
 		version = append(version, local...)
 	}
-	conn, err := utils.ConnectByConfig(ctx, config, options...)
-	if err != nil {
-		return err
-	}
-	defer conn.Close(context.Background())
-	// Update migration history
-	if err = UpdateMigrationTable(ctx, conn, version, status, repairAll, fsys); err == nil {
-		utils.CmdSuggestion = fmt.Sprintf("Run %s to show the updated migration history.", utils.Aqua("supabase migration list"))
-	}
-	return err
+	return version, repairAll, nil
 }
 
 func UpdateMigrationTable(ctx context.Context, conn *pgx.Conn, version []string, status string, repairAll bool, fsys afero.Fs) error {
@@ -71,7 +97,10 @@ func UpdateMigrationTable(ctx context.Context, conn *pgx.Conn, version []string,
 			if err != nil {
 				return err
 			}
-			batch.Queue(migration.UPSERT_MIGRATION_VERSION, f.Version, f.Name, f.Statements)
+			batch.Queue(migration.UPSERT_MIGRATION_VERSION, f.Version, f.Name, f.Statements, f.Checksum())
+			if len(f.Rollback) > 0 {
+				batch.Queue(migration.UPDATE_MIGRATION_ROLLBACK, f.Version, f.Rollback)
+			}
 		}
 	case Reverted:
 		if !repairAll {
@@ -87,16 +116,57 @@ func UpdateMigrationTable(ctx context.Context, conn *pgx.Conn, version []string,
 	return nil
 }
 
+// updateMigrationHistory repairs the history through the given transport, ie. the Management API.
+func updateMigrationHistory(ctx context.Context, history migration.MigrationHistory, version []string, status string, repairAll bool, fsys afero.Fs) error {
+	var reverted []string
+	if repairAll {
+		// Without truncate, remote versions that are missing locally or unwanted must be reverted
+		remote, err := history.ListRemoteMigrations(ctx)
+		if err != nil {
+			return err
+		}
+		for _, v := range remote {
+			if status == Reverted || !slices.Contains(version, v) {
+				reverted = append(reverted, v)
+			}
+		}
+	} else if status == Reverted {
+		reverted = version
+	}
+	if err := history.MarkReverted(ctx, reverted); err != nil {
+		return err
+	}
+	if status == Applied {
+		var migrations []*migration.MigrationFile
+		for _, v := range version {
+			f, err := NewMigrationFromVersion(v, fsys)
+			if err != nil {
+				return err
+			}
+			migrations = append(migrations, f)
+		}
+		if err := history.MarkApplied(ctx, migrations); err != nil {
+			return err
+		}
+	}
+	if !repairAll {
+		fmt.Fprintf(os.Stderr, "Repaired migration history: %v => %s\n", version, status)
+	}
+	return nil
+}
+
 func GetMigrationFile(version string, fsys afero.Fs) (string, error) {
 	path := filepath.Join(utils.MigrationsDir, version+"_*.sql")
 	matches, err := afero.Glob(fsys, path)
 	if err != nil {
 		return "", errors.Errorf("failed to glob migration files: %w", err)
 	}
-	if len(matches) == 0 {
-		return "", errors.Errorf("glob %s: %w", path, os.ErrNotExist)
+	for _, m := range matches {
+		if !migration.IsDownFile(m) {
+			return m, nil
+		}
 	}
-	return matches[0], nil
+	return "", errors.Errorf("glob %s: %w", path, os.ErrNotExist)
 }
 
 func NewMigrationFromVersion(version string, fsys afero.Fs) (*migration.MigrationFile, error) {
@@ -2,17 +2,20 @@ package repair
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/h2non/gock"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v4"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/supabase/cli/internal/testing/apitest"
 	"github.com/supabase/cli/internal/testing/fstest"
 	"github.com/supabase/cli/internal/testing/helper"
 	"github.com/supabase/cli/internal/utils"
@@ -38,7 +41,7 @@ func TestRepairCommand(t *testing.T) {
 		conn := pgtest.NewConn()
 		defer conn.Close(t)
 		helper.MockMigrationHistory(conn).
-			Query(migration.UPSERT_MIGRATION_VERSION, "0", "test", []string{"select 1"}).
+			Query(migration.UPSERT_MIGRATION_VERSION, "0", "test", []string{"select 1"}, helper.MigrationHash("select 1")).
 			Reply("INSERT 0 1")
 		// Run test
 		err := Run(context.Background(), dbConfig, []string{"0"}, Applied, fsys, conn.Intercept)
@@ -88,7 +91,7 @@ func TestRepairCommand(t *testing.T) {
 		conn := pgtest.NewConn()
 		defer conn.Close(t)
 		helper.MockMigrationHistory(conn).
-			Query(migration.UPSERT_MIGRATION_VERSION, "0", "test", nil).
+			Query(migration.UPSERT_MIGRATION_VERSION, "0", "test", nil, helper.MigrationHash()).
 			ReplyError(pgerrcode.DuplicateObject, `relation "supabase_migrations.schema_migrations" does not exist`)
 		// Run test
 		err := Run(context.Background(), dbConfig, []string{"0"}, Applied, fsys, conn.Intercept)
@@ -110,7 +113,7 @@ func TestRepairAll(t *testing.T) {
 		helper.MockMigrationHistory(conn).
 			Query(strings.Join([]string{
 				migration.TRUNCATE_VERSION_TABLE,
-				strings.ReplaceAll(migration.UPSERT_MIGRATION_VERSION, "$1, $2, $3", " '0' ,  'test' ,  '{select 1}' "),
+				strings.ReplaceAll(migration.UPSERT_MIGRATION_VERSION, "$1, $2, $3, $4", " '0' ,  'test' ,  '{select 1}' ,  '"+helper.MigrationHash("select 1")+"' "),
 			}, ";")).
 			Reply("TRUNCATE TABLE").
 			Reply("INSERT 0 1")
@@ -157,3 +160,64 @@ func TestRepairAll(t *testing.T) {
 		assert.ErrorIs(t, err, os.ErrPermission)
 	})
 }
+
+func TestRepairViaApi(t *testing.T) {
+	// Setup valid access token
+	token := apitest.RandomAccessToken(t)
+	t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+	// Setup valid project ref
+	project := apitest.RandomProjectRef()
+
+	t.Run("throws error on reverting through api", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		// Run test
+		err := RunViaApi(context.Background(), project, []string{"0"}, Reverted, fsys)
+		// Check error
+		assert.ErrorIs(t, err, migration.ErrRevertViaApi)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("applies versions through migrations endpoint", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		path := filepath.Join(utils.MigrationsDir, "0_test.sql")
+		require.NoError(t, afero.WriteFile(fsys, path, []byte("select 1"), 0644))
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/database/migrations").
+			Reply(http.StatusOK).
+			JSON([]map[string]string{})
+		gock.New(utils.DefaultApiHost).
+			Put("/v1/projects/"+project+"/database/migrations").
+			MatchHeader("Idempotency-Key", "0").
+			Reply(http.StatusOK)
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/database/migrations").
+			Reply(http.StatusOK).
+			JSON([]map[string]string{{"version": "0"}})
+		// Run test
+		err := RunViaApi(context.Background(), project, []string{"0"}, Applied, fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on list failure", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		path := filepath.Join(utils.MigrationsDir, "0_test.sql")
+		require.NoError(t, afero.WriteFile(fsys, path, []byte("select 1"), 0644))
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/database/migrations").
+			Reply(http.StatusServiceUnavailable)
+		// Run test
+		err := RunViaApi(context.Background(), project, []string{"0"}, Applied, fsys)
+		// Check error
+		assert.ErrorContains(t, err, "unexpected list migrations status 503:")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+}
@@ -182,7 +182,7 @@ func baselineMigrations(ctx context.Context, config pgconn.Config, version strin
 	// Data statements don't mutate schemas, safe to use statement cache
 	batch := pgx.Batch{}
 	batch.Queue(migration.DELETE_MIGRATION_BEFORE, m.Version)
-	batch.Queue(migration.INSERT_MIGRATION_VERSION, m.Version, m.Name, m.Statements)
+	batch.Queue(migration.INSERT_MIGRATION_VERSION, m.Version, m.Name, m.Statements, m.Checksum())
 	if err := conn.SendBatch(ctx, &batch).Close(); err != nil {
 		return errors.Errorf("failed to update migration history: %w", err)
 	}
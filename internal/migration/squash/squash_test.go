@@ -88,11 +88,11 @@ func TestSquashCommand(t *testing.T) {
 			Reply("RESET").
 			Query(sql).
 			Reply("CREATE SCHEMA").
-			Query(migration.INSERT_MIGRATION_VERSION, "0", "init", []string{sql}).
+			Query(migration.INSERT_MIGRATION_VERSION, "0", "init", []string{sql}, helper.MigrationHash(sql)).
 			Reply("INSERT 0 1").
 			Query("RESET ALL").
 			Reply("RESET").
-			Query(migration.INSERT_MIGRATION_VERSION, "1", "target", nil).
+			Query(migration.INSERT_MIGRATION_VERSION, "1", "target", nil, helper.MigrationHash()).
 			Reply("INSERT 0 1")
 		// Run test
 		err := Run(context.Background(), "", pgconn.Config{
@@ -120,7 +120,7 @@ func TestSquashCommand(t *testing.T) {
 		conn := pgtest.NewConn()
 		defer conn.Close(t)
 		helper.MockMigrationHistory(conn).
-			Query(fmt.Sprintf("DELETE FROM supabase_migrations.schema_migrations WHERE version <=  '0' ;INSERT INTO supabase_migrations.schema_migrations(version, name, statements) VALUES( '0' ,  'init' ,  '{%s}' )", sql)).
+			Query(fmt.Sprintf("DELETE FROM supabase_migrations.schema_migrations WHERE version <=  '0' ;INSERT INTO supabase_migrations.schema_migrations(version, name, statements, hash) VALUES( '0' ,  'init' ,  '{%s}' ,  '%s' )", sql, helper.MigrationHash(sql))).
 			Reply("INSERT 0 1")
 		// Run test
 		err := Run(context.Background(), "0", dbConfig, fsys, conn.Intercept, func(cc *pgx.ConnConfig) {
@@ -315,7 +315,7 @@ func TestSquashMigrations(t *testing.T) {
 			Reply("RESET").
 			Query(sql).
 			Reply("CREATE SCHEMA").
-			Query(migration.INSERT_MIGRATION_VERSION, "0", "init", []string{sql}).
+			Query(migration.INSERT_MIGRATION_VERSION, "0", "init", []string{sql}, helper.MigrationHash(sql)).
 			Reply("INSERT 0 1")
 		// Run test
 		err := squashMigrations(context.Background(), []string{path}, afero.NewReadOnlyFs(fsys), conn.Intercept)
@@ -340,7 +340,7 @@ func TestBaselineMigration(t *testing.T) {
 		conn := pgtest.NewConn()
 		defer conn.Close(t)
 		helper.MockMigrationHistory(conn).
-			Query(fmt.Sprintf("DELETE FROM supabase_migrations.schema_migrations WHERE version <=  '0' ;INSERT INTO supabase_migrations.schema_migrations(version, name, statements) VALUES( '0' ,  'init' ,  '{%s}' )", sql)).
+			Query(fmt.Sprintf("DELETE FROM supabase_migrations.schema_migrations WHERE version <=  '0' ;INSERT INTO supabase_migrations.schema_migrations(version, name, statements, hash) VALUES( '0' ,  'init' ,  '{%s}' ,  '%s' )", sql, helper.MigrationHash(sql))).
 			Reply("INSERT 0 1")
 		// Run test
 		err := baselineMigrations(context.Background(), dbConfig, "", fsys, conn.Intercept, func(cc *pgx.ConnConfig) {
@@ -368,7 +368,7 @@ func TestBaselineMigration(t *testing.T) {
 		conn := pgtest.NewConn()
 		defer conn.Close(t)
 		helper.MockMigrationHistory(conn).
-			Query(fmt.Sprintf("DELETE FROM supabase_migrations.schema_migrations WHERE version <=  '%[1]s' ;INSERT INTO supabase_migrations.schema_migrations(version, name, statements) VALUES( '%[1]s' ,  'init' ,  null )", "0")).
+			Query(fmt.Sprintf("DELETE FROM supabase_migrations.schema_migrations WHERE version <=  '%[1]s' ;INSERT INTO supabase_migrations.schema_migrations(version, name, statements, hash) VALUES( '%[1]s' ,  'init' ,  null ,  '%[2]s' )", "0", helper.MigrationHash())).
 			ReplyError(pgerrcode.InsufficientPrivilege, "permission denied for relation supabase_migrations")
 		// Run test
 		err := baselineMigrations(context.Background(), dbConfig, "0", fsys, conn.Intercept, func(cc *pgx.ConnConfig) {
@@ -31,7 +31,11 @@ func Run(ctx context.Context, includeAll bool, config pgconn.Config, fsys afero.
 }
 
 func GetPendingMigrations(ctx context.Context, includeAll bool, conn *pgx.Conn, fsys afero.Fs) ([]string, error) {
-	remoteMigrations, err := migration.ListRemoteMigrations(ctx, conn)
+	return ListPendingMigrations(ctx, includeAll, migration.NewPgHistory(conn), fsys)
+}
+
+func ListPendingMigrations(ctx context.Context, includeAll bool, history migration.MigrationHistory, fsys afero.Fs) ([]string, error) {
+	remoteMigrations, err := history.ListRemoteMigrations(ctx)
 	if err != nil {
 		return nil, err
 	}
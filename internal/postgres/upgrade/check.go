@@ -0,0 +1,124 @@
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func RunCheck(ctx context.Context, projectRef string) error {
+	result, err := GetEligibility(ctx, projectRef)
+	if err != nil {
+		return err
+	}
+	if utils.OutputFormat.Value != utils.OutputPretty {
+		return utils.EncodeOutput(utils.OutputFormat.Value, os.Stdout, result)
+	}
+	return printEligibility(result)
+}
+
+func GetEligibility(ctx context.Context, projectRef string) (api.ProjectUpgradeEligibilityResponse, error) {
+	resp, err := utils.GetSupabase().V1GetPostgresUpgradeEligibilityWithResponse(ctx, projectRef)
+	if err != nil {
+		return api.ProjectUpgradeEligibilityResponse{}, errors.Errorf("failed to check upgrade eligibility: %w", err)
+	} else if resp.JSON200 == nil {
+		return api.ProjectUpgradeEligibilityResponse{}, errors.Errorf("unexpected upgrade eligibility status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	return *resp.JSON200, nil
+}
+
+func printEligibility(result api.ProjectUpgradeEligibilityResponse) error {
+	fmt.Fprintln(os.Stderr, "Current version:", utils.Aqua(result.CurrentAppVersion))
+	if !result.Eligible {
+		fmt.Fprintln(os.Stderr, "Your project is", utils.Red("not eligible"), "for a Postgres upgrade.")
+	} else {
+		fmt.Fprintf(os.Stderr, "Your project is %s for a Postgres upgrade (estimated duration: %.1f hours).\n", utils.Green("eligible"), result.DurationEstimateHours)
+	}
+	table := `|TARGET VERSION|POSTGRES VERSION|RELEASE CHANNEL|
+|-|-|-|
+`
+	for _, v := range result.TargetUpgradeVersions {
+		table += fmt.Sprintf("|`%s`|`%s`|`%s`|\n", v.AppVersion, v.PostgresVersion, v.ReleaseChannel)
+	}
+	if blockers := ListBlockers(result); len(blockers) > 0 {
+		table += `
+|BLOCKING ISSUE|OBJECT|
+|-|-|
+`
+		for _, b := range blockers {
+			table += fmt.Sprintf("|`%s`|`%s`|\n", b.Type, strings.ReplaceAll(b.Object, "|", "\\|"))
+		}
+	}
+	return utils.RenderTable(table)
+}
+
+type Blocker struct {
+	Type   string `json:"type"`
+	Object string `json:"object"`
+}
+
+// ListBlockers describes the validation errors that prevent a project from being upgraded.
+func ListBlockers(result api.ProjectUpgradeEligibilityResponse) []Blocker {
+	var blockers []Blocker
+	for _, item := range result.ValidationErrors {
+		blockers = append(blockers, toBlocker(item))
+	}
+	for _, role := range result.LegacyAuthCustomRoles {
+		blockers = append(blockers, Blocker{Type: "legacy_auth_custom_role", Object: role})
+	}
+	return blockers
+}
+
+func toBlocker(item api.ProjectUpgradeEligibilityResponse_ValidationErrors_Item) Blocker {
+	var result Blocker
+	data, err := item.MarshalJSON()
+	if err != nil {
+		return result
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result
+	}
+	switch result.Type {
+	case string(api.ObjectsDependingOnPgCron):
+		if v, err := item.AsProjectUpgradeEligibilityResponseValidationErrors0(); err == nil {
+			result.Object = strings.Join(v.Dependents, ", ")
+		}
+	case string(api.IndexesReferencingLlToEarth):
+		if v, err := item.AsProjectUpgradeEligibilityResponseValidationErrors1(); err == nil {
+			result.Object = fmt.Sprintf("%s.%s (%s)", v.SchemaName, v.TableName, v.IndexName)
+		}
+	case string(api.FunctionUsingObsoleteLang):
+		if v, err := item.AsProjectUpgradeEligibilityResponseValidationErrors2(); err == nil {
+			result.Object = fmt.Sprintf("%s.%s (%s)", v.SchemaName, v.FunctionName, v.LangName)
+		}
+	case string(api.UnsupportedExtension):
+		if v, err := item.AsProjectUpgradeEligibilityResponseValidationErrors3(); err == nil {
+			result.Object = v.ExtensionName
+		}
+	case string(api.UnsupportedFdwHandler):
+		if v, err := item.AsProjectUpgradeEligibilityResponseValidationErrors4(); err == nil {
+			result.Object = fmt.Sprintf("%s (%s)", v.FdwName, v.FdwHandlerName)
+		}
+	case string(api.UnloggedTableWithPersistentSequence):
+		if v, err := item.AsProjectUpgradeEligibilityResponseValidationErrors5(); err == nil {
+			result.Object = fmt.Sprintf("%s.%s (%s)", v.SchemaName, v.TableName, v.SequenceName)
+		}
+	case string(api.UserDefinedObjectsInInternalSchemas):
+		if v, err := item.AsProjectUpgradeEligibilityResponseValidationErrors6(); err == nil {
+			result.Object = fmt.Sprintf("%s %s.%s", v.ObjType, v.SchemaName, v.ObjName)
+		}
+	case string(api.ActiveReplicationSlot):
+		if v, err := item.AsProjectUpgradeEligibilityResponseValidationErrors7(); err == nil {
+			result.Object = v.SlotName
+		}
+	default:
+		result.Object = string(data)
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/go-errors/errors"
+	"github.com/spf13/afero"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/flags"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func RunStart(ctx context.Context, projectRef, targetVersion, releaseChannel string, fsys afero.Fs) error {
+	eligibility, err := GetEligibility(ctx, projectRef)
+	if err != nil {
+		return err
+	}
+	if err := printEligibility(eligibility); err != nil {
+		return err
+	}
+	if !eligibility.Eligible {
+		utils.CmdSuggestion = "Resolve the blocking issues above before upgrading."
+		return errors.New("project is not eligible for upgrade")
+	}
+	if len(targetVersion) == 0 {
+		if len(eligibility.TargetUpgradeVersions) == 0 {
+			return errors.New("no target version available for upgrade")
+		}
+		target := eligibility.TargetUpgradeVersions[0]
+		targetVersion = string(target.PostgresVersion)
+		if len(releaseChannel) == 0 {
+			releaseChannel = string(target.ReleaseChannel)
+		}
+	}
+	title := fmt.Sprintf("Do you want to upgrade project %s to Postgres %s? Your project will be offline during the upgrade.", utils.Aqua(projectRef), utils.Aqua(targetVersion))
+	if shouldUpgrade, err := utils.NewConsole().PromptYesNo(ctx, title, false); err != nil {
+		return err
+	} else if !shouldUpgrade {
+		return errors.New(context.Canceled)
+	}
+	body := api.UpgradeDatabaseBody{TargetVersion: targetVersion}
+	if len(releaseChannel) > 0 {
+		body.ReleaseChannel = (*api.UpgradeDatabaseBodyReleaseChannel)(&releaseChannel)
+	}
+	resp, err := utils.GetSupabase().V1UpgradePostgresVersionWithResponse(ctx, projectRef, body)
+	if err != nil {
+		return errors.Errorf("failed to upgrade Postgres: %w", err)
+	} else if resp.JSON201 == nil {
+		return errors.Errorf("unexpected upgrade Postgres status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	fmt.Fprintln(os.Stderr, "Started Postgres upgrade with tracking ID:", utils.Aqua(resp.JSON201.TrackingId))
+	if _, err := WaitForUpgrade(ctx, projectRef, resp.JSON201.TrackingId); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "Finished Postgres upgrade:", utils.Aqua(projectRef))
+	return updateMajorVersion(ctx, targetVersion, fsys)
+}
+
+var (
+	majorPattern   = regexp.MustCompile(`^\d+`)
+	versionPattern = regexp.MustCompile(`(?m)^major_version\s*=\s*\d+`)
+)
+
+func updateMajorVersion(ctx context.Context, targetVersion string, fsys afero.Fs) error {
+	majorVersion, err := strconv.ParseUint(majorPattern.FindString(targetVersion), 10, 7)
+	if err != nil {
+		return errors.Errorf("invalid major version: %w", err)
+	}
+	if err := flags.LoadConfig(fsys); err != nil {
+		fmt.Fprintln(utils.GetDebugLogger(), err)
+		return nil
+	} else if uint64(utils.Config.Db.MajorVersion) == majorVersion {
+		return nil
+	}
+	title := fmt.Sprintf("Do you want to update db.major_version in %s to %d?", utils.Bold(utils.ConfigPath), majorVersion)
+	if shouldUpdate, err := utils.NewConsole().PromptYesNo(ctx, title, true); err != nil || !shouldUpdate {
+		return err
+	}
+	data, err := afero.ReadFile(fsys, utils.ConfigPath)
+	if err != nil {
+		return errors.Errorf("failed to read config: %w", err)
+	}
+	if !versionPattern.Match(data) {
+		fmt.Fprintf(os.Stderr, `Update your %s manually:
+[db]
+major_version = %d
+`, utils.Bold(utils.ConfigPath), majorVersion)
+		return nil
+	}
+	updated := versionPattern.ReplaceAllLiteral(data, []byte(fmt.Sprintf("major_version = %d", majorVersion)))
+	return utils.WriteFile(utils.ConfigPath, updated, fsys)
+}
@@ -0,0 +1,196 @@
+package upgrade
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/cli/internal/testing/apitest"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+	"github.com/supabase/cli/pkg/cast"
+)
+
+func TestStartUpgrade(t *testing.T) {
+	// Setup valid project ref
+	project := apitest.RandomProjectRef()
+	// Setup valid access token
+	token := apitest.RandomAccessToken(t)
+	t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+	viper.Set("YES", true)
+	defer viper.Set("YES", false)
+	pollInterval = time.Millisecond
+
+	t.Run("upgrades and bumps local major version", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		require.NoError(t, utils.WriteFile(utils.ConfigPath, []byte(`project_id = "test"
+
+[db]
+port = 54322
+major_version = 15
+`), fsys))
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/upgrade/eligibility").
+			Reply(http.StatusOK).
+			JSON(map[string]any{
+				"eligible":            true,
+				"current_app_version": "supabase-postgres-15.8.1.085",
+				"target_upgrade_versions": []map[string]any{{
+					"postgres_version": "17",
+					"release_channel":  "ga",
+					"app_version":      "supabase-postgres-17.4.1.054",
+				}},
+			})
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/" + project + "/upgrade").
+			JSON(api.UpgradeDatabaseBody{
+				TargetVersion:  "17",
+				ReleaseChannel: cast.Ptr(api.UpgradeDatabaseBodyReleaseChannelGa),
+			}).
+			Reply(http.StatusCreated).
+			JSON(api.ProjectUpgradeInitiateResponse{TrackingId: "test-id"})
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/"+project+"/upgrade/status").
+			MatchParam("tracking_id", "test-id").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"databaseUpgradeStatus": map[string]any{
+				"progress":       "5_initiated_data_upgrade",
+				"target_version": 17,
+			}})
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/"+project+"/upgrade/status").
+			MatchParam("tracking_id", "test-id").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"databaseUpgradeStatus": map[string]any{
+				"progress":       "9_completed_upgrade",
+				"target_version": 17,
+			}})
+		// Run test
+		err := RunStart(context.Background(), project, "", "", fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+		data, err := afero.ReadFile(fsys, utils.ConfigPath)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "major_version = 17\n")
+	})
+
+	t.Run("throws error on ineligible project", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/upgrade/eligibility").
+			Reply(http.StatusOK).
+			JSON(map[string]any{
+				"eligible": false,
+				"validation_errors": []map[string]any{{
+					"type":           "unsupported_extension",
+					"extension_name": "timescaledb",
+				}},
+			})
+		// Run test
+		err := RunStart(context.Background(), project, "", "", afero.NewMemMapFs())
+		// Check error
+		assert.ErrorContains(t, err, "project is not eligible for upgrade")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on failed upgrade", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/upgrade/status").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"databaseUpgradeStatus": map[string]any{
+				"progress": "5_initiated_data_upgrade",
+				"error":    "5_data_upgrade_completion_failed",
+			}})
+		// Run test
+		_, err := WaitForUpgrade(context.Background(), project, "")
+		// Check error
+		assert.ErrorContains(t, err, "failed to upgrade Postgres: 5_data_upgrade_completion_failed")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on missing upgrade", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/upgrade/status").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"databaseUpgradeStatus": nil})
+		// Run test
+		_, err := WaitForUpgrade(context.Background(), project, "")
+		// Check error
+		assert.ErrorIs(t, err, ErrNoUpgrade)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on failed status", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/upgrade/status").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"databaseUpgradeStatus": map[string]any{
+				"progress": api.N7DetachedVolumeFromOriginalInstance,
+				"status":   statusFailed,
+			}})
+		// Run test
+		_, err := WaitForUpgrade(context.Background(), project, "")
+		// Check error
+		assert.ErrorContains(t, err, "failed to upgrade Postgres: -")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("stops waiting on upgraded status", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/upgrade/status").
+			Reply(http.StatusOK).
+			JSON(map[string]any{"databaseUpgradeStatus": map[string]any{
+				"progress": api.N8AttachedVolumeToUpgradedInstance,
+				"status":   statusUpgraded,
+			}})
+		// Run test
+		progress, err := WaitForUpgrade(context.Background(), project, "")
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, api.N8AttachedVolumeToUpgradedInstance, progress)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+}
+
+func TestListBlockers(t *testing.T) {
+	var result api.ProjectUpgradeEligibilityResponse
+	items := []string{
+		`{"type":"unsupported_extension","extension_name":"timescaledb"}`,
+		`{"type":"user_defined_objects_in_internal_schemas","obj_type":"table","schema_name":"auth","obj_name":"profiles"}`,
+		`{"type":"active_replication_slot","slot_name":"slot_1"}`,
+	}
+	for _, v := range items {
+		var item api.ProjectUpgradeEligibilityResponse_ValidationErrors_Item
+		require.NoError(t, item.UnmarshalJSON([]byte(v)))
+		result.ValidationErrors = append(result.ValidationErrors, item)
+	}
+	result.LegacyAuthCustomRoles = []string{"admin"}
+	// Run test
+	blockers := ListBlockers(result)
+	// Check result
+	assert.Equal(t, []Blocker{
+		{Type: "unsupported_extension", Object: "timescaledb"},
+		{Type: "user_defined_objects_in_internal_schemas", Object: "table auth.profiles"},
+		{Type: "active_replication_slot", Object: "slot_1"},
+		{Type: "legacy_auth_custom_role", Object: "admin"},
+	}, blockers)
+}
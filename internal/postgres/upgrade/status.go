@@ -0,0 +1,122 @@
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func RunStatus(ctx context.Context, projectRef, trackingId string, follow bool) error {
+	if follow {
+		_, err := WaitForUpgrade(ctx, projectRef, trackingId)
+		return err
+	}
+	result, err := GetStatus(ctx, projectRef, trackingId)
+	if err != nil {
+		return err
+	}
+	if utils.OutputFormat.Value != utils.OutputPretty {
+		return utils.EncodeOutput(utils.OutputFormat.Value, os.Stdout, result)
+	}
+	status, err := result.DatabaseUpgradeStatus.Get()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ErrNoUpgrade)
+		return nil
+	}
+	table := `|TARGET VERSION|PROGRESS|ERROR|INITIATED AT|LATEST STATUS AT|
+|-|-|-|-|-|
+`
+	table += fmt.Sprintf(
+		"|`%v`|`%s`|`%s`|`%s`|`%s`|\n",
+		status.TargetVersion,
+		formatProgress(status.Progress),
+		formatError(status.Error),
+		status.InitiatedAt,
+		status.LatestStatusAt,
+	)
+	return utils.RenderTable(table)
+}
+
+func GetStatus(ctx context.Context, projectRef, trackingId string) (api.DatabaseUpgradeStatusResponse, error) {
+	params := api.V1GetPostgresUpgradeStatusParams{}
+	if len(trackingId) > 0 {
+		params.TrackingId = &trackingId
+	}
+	resp, err := utils.GetSupabase().V1GetPostgresUpgradeStatusWithResponse(ctx, projectRef, &params)
+	if err != nil {
+		return api.DatabaseUpgradeStatusResponse{}, errors.Errorf("failed to get upgrade status: %w", err)
+	} else if resp.JSON200 == nil {
+		return api.DatabaseUpgradeStatusResponse{}, errors.Errorf("unexpected upgrade status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	return *resp.JSON200, nil
+}
+
+// Used by unit tests
+var pollInterval = 10 * time.Second
+
+// Numeric upgrade status reported alongside progress by the platform.
+const (
+	statusUpgrading = 0
+	statusUpgraded  = 1
+	statusFailed    = 2
+)
+
+var ErrNoUpgrade = errors.New("No Postgres upgrade has been initiated.")
+
+// WaitForUpgrade polls the upgrade status until it completes, returning the final progress.
+func WaitForUpgrade(ctx context.Context, projectRef, trackingId string) (api.DatabaseUpgradeStatusResponseDatabaseUpgradeStatusProgress, error) {
+	var last api.DatabaseUpgradeStatusResponseDatabaseUpgradeStatusProgress
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		result, err := GetStatus(ctx, projectRef, trackingId)
+		if err != nil {
+			return last, err
+		}
+		status, err := result.DatabaseUpgradeStatus.Get()
+		if err != nil {
+			// Null status means there is nothing to wait for
+			return last, errors.New(ErrNoUpgrade)
+		}
+		if status.Error != nil {
+			return last, errors.Errorf("failed to upgrade Postgres: %s", formatError(status.Error))
+		}
+		if status.Progress != nil && *status.Progress != last {
+			last = *status.Progress
+			fmt.Fprintln(os.Stderr, "Upgrade progress:", utils.Aqua(formatProgress(status.Progress)))
+		}
+		switch status.Status {
+		case statusUpgraded:
+			return last, nil
+		case statusFailed:
+			return last, errors.Errorf("failed to upgrade Postgres: %s", formatError(status.Error))
+		}
+		if last == api.N9CompletedUpgrade || last == api.N10CompletedPostPhysicalBackup {
+			return last, nil
+		}
+		select {
+		case <-ctx.Done():
+			return last, errors.New(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func formatProgress(progress *api.DatabaseUpgradeStatusResponseDatabaseUpgradeStatusProgress) string {
+	if progress == nil {
+		return "-"
+	}
+	return string(*progress)
+}
+
+func formatError(e *api.DatabaseUpgradeStatusResponseDatabaseUpgradeStatusError) string {
+	if e == nil {
+		return "-"
+	}
+	return string(*e)
+}
@@ -0,0 +1,25 @@
+package create
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/flags"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func Run(ctx context.Context, region string) error {
+	body := api.SetUpReadReplicaBody{ReadReplicaRegion: api.SetUpReadReplicaBodyReadReplicaRegion(region)}
+	resp, err := utils.GetSupabase().V1SetupAReadReplicaWithResponse(ctx, flags.ProjectRef, body)
+	if err != nil {
+		return errors.Errorf("failed to create read replica: %w", err)
+	} else if resp.StatusCode() != http.StatusCreated {
+		return errors.Errorf("unexpected create read replica status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	fmt.Fprintln(os.Stderr, "Started read replica setup in region:", utils.Aqua(utils.FormatRegion(region)))
+	return nil
+}
@@ -0,0 +1,64 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+	"github.com/supabase/cli/internal/testing/apitest"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/flags"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func TestCreateReplica(t *testing.T) {
+	// Setup valid project ref
+	flags.ProjectRef = apitest.RandomProjectRef()
+	// Setup valid access token
+	token := apitest.RandomAccessToken(t)
+	t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+
+	t.Run("creates read replica", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/" + flags.ProjectRef + "/read-replicas/setup").
+			JSON(api.SetUpReadReplicaBody{ReadReplicaRegion: api.SetUpReadReplicaBodyReadReplicaRegionUsEast1}).
+			Reply(http.StatusCreated)
+		// Run test
+		err := Run(context.Background(), "us-east-1")
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on network error", func(t *testing.T) {
+		errNetwork := errors.New("network error")
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/" + flags.ProjectRef + "/read-replicas/setup").
+			ReplyError(errNetwork)
+		// Run test
+		err := Run(context.Background(), "us-east-1")
+		// Check error
+		assert.ErrorIs(t, err, errNetwork)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on service unavailable", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/" + flags.ProjectRef + "/read-replicas/setup").
+			Reply(http.StatusServiceUnavailable)
+		// Run test
+		err := Run(context.Background(), "us-east-1")
+		// Check error
+		assert.ErrorContains(t, err, "unexpected create read replica status 503:")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+}
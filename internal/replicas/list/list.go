@@ -0,0 +1,61 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/flags"
+	"github.com/supabase/cli/pkg/api"
+)
+
+// Run lists the primary database and read replicas of a project. The Management API has no
+// endpoint for listing replicas, so their identifiers are read from the pooler config instead.
+func Run(ctx context.Context) error {
+	resp, err := utils.GetSupabase().V1GetPoolerConfigWithResponse(ctx, flags.ProjectRef)
+	if err != nil {
+		return errors.Errorf("failed to list databases: %w", err)
+	} else if resp.JSON200 == nil {
+		return errors.Errorf("unexpected list databases status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	databases := UniqueDatabases(*resp.JSON200)
+	switch utils.OutputFormat.Value {
+	case utils.OutputPretty:
+		return utils.RenderTable(ToMarkdown(databases))
+	case utils.OutputEnv:
+		return errors.New(utils.ErrEnvNotSupported)
+	}
+	return utils.EncodeOutput(utils.OutputFormat.Value, os.Stdout, databases)
+}
+
+// UniqueDatabases keeps the first config of each database, because the pooler returns one
+// config per pool mode.
+func UniqueDatabases(configs []api.SupavisorConfigResponse) []api.SupavisorConfigResponse {
+	var result []api.SupavisorConfigResponse
+	seen := map[string]struct{}{}
+	for _, c := range configs {
+		if _, ok := seen[c.Identifier]; !ok {
+			seen[c.Identifier] = struct{}{}
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func ToMarkdown(databases []api.SupavisorConfigResponse) string {
+	table := `|IDENTIFIER|TYPE|HOST|PORT|
+|-|-|-|-|
+`
+	for _, db := range databases {
+		table += fmt.Sprintf(
+			"|`%s`|`%s`|`%s`|`%d`|\n",
+			db.Identifier,
+			db.DatabaseType,
+			db.DbHost,
+			db.DbPort,
+		)
+	}
+	return table
+}
@@ -0,0 +1,78 @@
+package list
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+	"github.com/supabase/cli/internal/testing/apitest"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/flags"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func TestListReplicas(t *testing.T) {
+	// Setup valid project ref
+	flags.ProjectRef = apitest.RandomProjectRef()
+	// Setup valid access token
+	token := apitest.RandomAccessToken(t)
+	t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+
+	t.Run("lists database identifiers", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + flags.ProjectRef + "/config/database/pooler").
+			Reply(http.StatusOK).
+			JSON([]api.SupavisorConfigResponse{{
+				Identifier:   flags.ProjectRef,
+				DatabaseType: api.SupavisorConfigResponseDatabaseTypePRIMARY,
+				DbHost:       "db.supabase.co",
+				DbPort:       5432,
+			}, {
+				Identifier:   flags.ProjectRef + "-rr-us-east-1-abcde",
+				DatabaseType: api.SupavisorConfigResponseDatabaseTypeREADREPLICA,
+				DbHost:       "db.supabase.co",
+				DbPort:       5432,
+			}})
+		// Run test
+		err := Run(context.Background())
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on service unavailable", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + flags.ProjectRef + "/config/database/pooler").
+			Reply(http.StatusServiceUnavailable)
+		// Run test
+		err := Run(context.Background())
+		// Check error
+		assert.ErrorContains(t, err, "unexpected list databases status 503:")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+}
+
+func TestUniqueDatabases(t *testing.T) {
+	primary := api.SupavisorConfigResponse{
+		Identifier:   "test",
+		DatabaseType: api.SupavisorConfigResponseDatabaseTypePRIMARY,
+		PoolMode:     api.SupavisorConfigResponsePoolModeTransaction,
+	}
+	replica := api.SupavisorConfigResponse{
+		Identifier:   "test-rr-us-east-1-abcde",
+		DatabaseType: api.SupavisorConfigResponseDatabaseTypeREADREPLICA,
+		PoolMode:     api.SupavisorConfigResponsePoolModeTransaction,
+	}
+	session := primary
+	session.PoolMode = api.SupavisorConfigResponsePoolModeSession
+	// Run test
+	databases := UniqueDatabases([]api.SupavisorConfigResponse{primary, session, replica})
+	// Check error
+	assert.Equal(t, []api.SupavisorConfigResponse{primary, replica}, databases)
+}
@@ -0,0 +1,25 @@
+package remove
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/go-errors/errors"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/flags"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func Run(ctx context.Context, identifier string) error {
+	body := api.RemoveReadReplicaBody{DatabaseIdentifier: identifier}
+	resp, err := utils.GetSupabase().V1RemoveAReadReplicaWithResponse(ctx, flags.ProjectRef, body)
+	if err != nil {
+		return errors.Errorf("failed to remove read replica: %w", err)
+	} else if resp.StatusCode() != http.StatusCreated {
+		return errors.Errorf("unexpected remove read replica status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	fmt.Fprintln(os.Stderr, "Started removing read replica:", utils.Aqua(identifier))
+	return nil
+}
@@ -0,0 +1,65 @@
+package remove
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+	"github.com/supabase/cli/internal/testing/apitest"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/flags"
+	"github.com/supabase/cli/pkg/api"
+)
+
+func TestRemoveReplica(t *testing.T) {
+	// Setup valid project ref
+	flags.ProjectRef = apitest.RandomProjectRef()
+	// Setup valid access token
+	token := apitest.RandomAccessToken(t)
+	t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+	identifier := flags.ProjectRef + "-rr-us-east-1-abcde"
+
+	t.Run("removes read replica", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/" + flags.ProjectRef + "/read-replicas/remove").
+			JSON(api.RemoveReadReplicaBody{DatabaseIdentifier: identifier}).
+			Reply(http.StatusCreated)
+		// Run test
+		err := Run(context.Background(), identifier)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on network error", func(t *testing.T) {
+		errNetwork := errors.New("network error")
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/" + flags.ProjectRef + "/read-replicas/remove").
+			ReplyError(errNetwork)
+		// Run test
+		err := Run(context.Background(), identifier)
+		// Check error
+		assert.ErrorIs(t, err, errNetwork)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error on service unavailable", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/" + flags.ProjectRef + "/read-replicas/remove").
+			Reply(http.StatusServiceUnavailable)
+		// Run test
+		err := Run(context.Background(), identifier)
+		// Check error
+		assert.ErrorContains(t, err, "unexpected remove read replica status 503:")
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+}
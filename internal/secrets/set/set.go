@@ -17,7 +17,7 @@ import (
 	"github.com/supabase/cli/pkg/api"
 )
 
-func Run(ctx context.Context, projectRef, envFilePath string, args []string, fsys afero.Fs) error {
+func Run(ctx context.Context, projectRef, slug, envFilePath string, args []string, fsys afero.Fs) error {
 	// 1. Sanity checks.
 	if err := flags.LoadConfig(fsys); err != nil {
 		fmt.Fprintln(utils.GetDebugLogger(), err)
@@ -25,9 +25,24 @@ func Run(ctx context.Context, projectRef, envFilePath string, args []string, fsy
 	if len(envFilePath) > 0 && !filepath.IsAbs(envFilePath) {
 		envFilePath = filepath.Join(utils.CurrentDirAbs, envFilePath)
 	}
-	secrets, err := ListSecrets(envFilePath, fsys, args...)
-	if err != nil {
-		return err
+	var secrets api.CreateSecretBody
+	if len(slug) > 0 {
+		if err := utils.ValidateFunctionSlug(slug); err != nil {
+			return err
+		}
+		envMap, err := ListFunctionSecrets(slug, fsys)
+		if err != nil {
+			return err
+		}
+		if secrets, err = mergeSecrets(envMap, envFilePath, fsys, args...); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "WARN: project secrets are readable by all deployed Functions, not only %s.\n", utils.Aqua(slug))
+	} else {
+		var err error
+		if secrets, err = ListSecrets(envFilePath, fsys, args...); err != nil {
+			return err
+		}
 	}
 	if len(secrets) == 0 {
 		return errors.New("No arguments found. Use --env-file to read from a .env file.")
@@ -50,6 +65,41 @@ func ListSecrets(envFilePath string, fsys afero.Fs, envArgs ...string) (api.Crea
 			envMap[name] = secret.Value
 		}
 	}
+	return mergeSecrets(envMap, envFilePath, fsys, envArgs...)
+}
+
+// ListFunctionSecrets returns the secrets scoped to a single Function, read from the
+// [functions.<slug>.env] config and overridden by the .env file in the Function directory.
+func ListFunctionSecrets(slug string, fsys afero.Fs) (map[string]string, error) {
+	envMap := map[string]string{}
+	function := utils.Config.Functions[slug]
+	for name, secret := range function.Env {
+		if len(secret.SHA256) > 0 {
+			envMap[name] = secret.Value
+		}
+	}
+	functionDir := filepath.Join(utils.FunctionsDir, slug)
+	if len(function.Entrypoint) > 0 {
+		functionDir = filepath.Dir(function.Entrypoint)
+	}
+	envFilePath := filepath.Join(functionDir, ".env")
+	if f, err := fsys.Stat(envFilePath); err == nil && !f.IsDir() {
+		parsed, err := parseEnvFile(envFilePath, fsys)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(envMap, parsed)
+	}
+	for name := range envMap {
+		if strings.HasPrefix(name, "SUPABASE_") {
+			fmt.Fprintln(os.Stderr, "Env name cannot start with SUPABASE_, skipping: "+name)
+			delete(envMap, name)
+		}
+	}
+	return envMap, nil
+}
+
+func mergeSecrets(envMap map[string]string, envFilePath string, fsys afero.Fs, envArgs ...string) (api.CreateSecretBody, error) {
 	if len(envFilePath) > 0 {
 		parsed, err := parseEnvFile(envFilePath, fsys)
 		if err != nil {
@@ -2,6 +2,7 @@ package set
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"testing"
@@ -36,7 +37,7 @@ func TestSecretSetCommand(t *testing.T) {
 			JSON(dummy).
 			Reply(http.StatusCreated)
 		// Run test
-		err := Run(context.Background(), project, "", []string{dummyEnv}, fsys)
+		err := Run(context.Background(), project, "", "", []string{dummyEnv}, fsys)
 		// Check error
 		assert.NoError(t, err)
 		assert.Empty(t, apitest.ListUnmatchedRequests())
@@ -59,7 +60,45 @@ func TestSecretSetCommand(t *testing.T) {
 			JSON(dummy).
 			Reply(http.StatusCreated)
 		// Run test
-		err := Run(context.Background(), project, "/tmp/.env", []string{}, fsys)
+		err := Run(context.Background(), project, "", "/tmp/.env", []string{}, fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("Sets function scoped secrets", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		require.NoError(t, utils.WriteFile(utils.ConfigPath, []byte(`
+			project_id = "test"
+			[functions.hello.env]
+			stripe_key = "sk_config"
+			webhook_secret = "whsec"
+		`), fsys))
+		require.NoError(t, utils.WriteFile("supabase/functions/hello/.env", []byte("STRIPE_KEY=sk_file\nSUPABASE_URL=skipped"), fsys))
+		// Setup valid project ref
+		project := apitest.RandomProjectRef()
+		// Setup valid access token
+		token := apitest.RandomAccessToken(t)
+		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+		// Flush pending mocks after test execution
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/" + project + "/secrets").
+			MatchType("json").
+			AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+				var body api.CreateSecretBody
+				if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+					return false, err
+				}
+				return assert.ElementsMatch(t, api.CreateSecretBody{
+					{Name: "STRIPE_KEY", Value: "sk_file"},
+					{Name: "WEBHOOK_SECRET", Value: "whsec"},
+				}, body), nil
+			}).
+			Reply(http.StatusCreated)
+		// Run test
+		err := Run(context.Background(), project, "hello", "", []string{}, fsys)
 		// Check error
 		assert.NoError(t, err)
 		assert.Empty(t, apitest.ListUnmatchedRequests())
@@ -74,7 +113,7 @@ func TestSecretSetCommand(t *testing.T) {
 		token := apitest.RandomAccessToken(t)
 		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
 		// Run test
-		err := Run(context.Background(), project, "", []string{}, fsys)
+		err := Run(context.Background(), project, "", "", []string{}, fsys)
 		// Check error
 		assert.ErrorContains(t, err, "No arguments found. Use --env-file to read from a .env file.")
 	})
@@ -88,7 +127,7 @@ func TestSecretSetCommand(t *testing.T) {
 		token := apitest.RandomAccessToken(t)
 		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
 		// Run test
-		err := Run(context.Background(), project, "", []string{"malformed"}, fsys)
+		err := Run(context.Background(), project, "", "", []string{"malformed"}, fsys)
 		// Check error
 		assert.ErrorContains(t, err, "Invalid secret pair: malformed. Must be NAME=VALUE.")
 	})
@@ -109,7 +148,7 @@ func TestSecretSetCommand(t *testing.T) {
 			JSON(dummy).
 			ReplyError(errors.New("network error"))
 		// Run test
-		err := Run(context.Background(), project, "", []string{dummyEnv}, fsys)
+		err := Run(context.Background(), project, "", "", []string{dummyEnv}, fsys)
 		// Check error
 		assert.ErrorContains(t, err, "network error")
 		assert.Empty(t, apitest.ListUnmatchedRequests())
@@ -132,7 +171,7 @@ func TestSecretSetCommand(t *testing.T) {
 			Reply(500).
 			JSON(map[string]string{"message": "unavailable"})
 		// Run test
-		err := Run(context.Background(), project, "", []string{dummyEnv}, fsys)
+		err := Run(context.Background(), project, "", "", []string{dummyEnv}, fsys)
 		// Check error
 		assert.ErrorContains(t, err, `Unexpected error setting project secrets: {"message":"unavailable"}`)
 		assert.Empty(t, apitest.ListUnmatchedRequests())
@@ -14,6 +14,10 @@ func MockMigrationHistory(conn *pgtest.MockConn) *pgtest.MockConn {
 		Query(migration.ADD_STATEMENTS_COLUMN).
 		Reply("ALTER TABLE").
 		Query(migration.ADD_NAME_COLUMN).
+		Reply("ALTER TABLE").
+		Query(migration.ADD_ROLLBACK_COLUMN).
+		Reply("ALTER TABLE").
+		Query(migration.ADD_HASH_COLUMN).
 		Reply("ALTER TABLE")
 	return conn
 }
@@ -26,3 +30,8 @@ func MockSeedHistory(conn *pgtest.MockConn) *pgtest.MockConn {
 		Reply("CREATE TABLE")
 	return conn
 }
+
+func MigrationHash(statements ...string) string {
+	m := migration.MigrationFile{Statements: statements}
+	return m.Checksum()
+}
@@ -1,15 +1,10 @@
 package utils
 
 import (
-	"bufio"
 	"bytes"
 	"encoding/json"
-	"fmt"
 	"io"
-	"os"
-	"regexp"
 	"slices"
-	"strconv"
 	"strings"
 
 	"github.com/docker/docker/pkg/jsonmessage"
@@ -59,67 +54,6 @@ func ProcessPullOutput(out io.ReadCloser, p Program) error {
 	return nil
 }
 
-type DiffStream struct {
-	o bytes.Buffer
-	r *io.PipeReader
-	w *io.PipeWriter
-	p Program
-}
-
-func NewDiffStream(p Program) *DiffStream {
-	r, w := io.Pipe()
-	go func() {
-		if err := ProcessDiffProgress(p, r); err != nil {
-			fmt.Fprintln(os.Stderr, err)
-		}
-	}()
-	return &DiffStream{r: r, w: w, p: p}
-}
-
-func (c DiffStream) Stdout() io.Writer {
-	return &c.o
-}
-
-func (c DiffStream) Stderr() io.Writer {
-	return c.w
-}
-
-func (c DiffStream) Collect() ([]byte, error) {
-	if err := c.w.Close(); err != nil {
-		fmt.Fprintln(os.Stderr, "Failed to close stream:", err)
-	}
-	return ProcessDiffOutput(c.o.Bytes())
-}
-
-func ProcessDiffProgress(p Program, out io.Reader) error {
-	scanner := bufio.NewScanner(out)
-	re := regexp.MustCompile(`(.*)([[:digit:]]{2,3})%`)
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		if line == "Starting schema diff..." {
-			percentage := 0.0
-			p.Send(ProgressMsg(&percentage))
-		}
-
-		matches := re.FindStringSubmatch(line)
-		if len(matches) != 3 {
-			// TODO: emit actual error statements
-			continue
-		}
-
-		p.Send(StatusMsg(matches[1]))
-		percentage, err := strconv.ParseFloat(matches[2], 64)
-		if err != nil {
-			continue
-		}
-		percentage = percentage / 100
-		p.Send(ProgressMsg(&percentage))
-	}
-	p.Send(ProgressMsg(nil))
-	return scanner.Err()
-}
-
 type DiffDependencies struct {
 	Type string `json:"type"`
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/spf13/afero"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"github.com/supabase/cli/internal/jit/request"
 	"github.com/supabase/cli/internal/utils"
 	"github.com/supabase/cli/internal/utils/credentials"
 	"github.com/supabase/cli/pkg/api"
@@ -32,6 +33,7 @@ const (
 	local
 	linked
 	proxy
+	viaApi
 )
 
 var DbConfig pgconn.Config
@@ -39,7 +41,9 @@ var DbConfig pgconn.Config
 func ParseDatabaseConfig(ctx context.Context, flagSet *pflag.FlagSet, fsys afero.Fs) error {
 	// Changed flags take precedence over default values
 	var connType connection
-	if flag := flagSet.Lookup("db-url"); flag != nil && flag.Changed {
+	if value, err := flagSet.GetBool("via-api"); err == nil && value {
+		connType = viaApi
+	} else if flag := flagSet.Lookup("db-url"); flag != nil && flag.Changed {
 		connType = direct
 	} else if flag := flagSet.Lookup("local"); flag != nil && flag.Changed {
 		connType = local
@@ -101,6 +105,14 @@ func ParseDatabaseConfig(ctx context.Context, flagSet *pflag.FlagSet, fsys afero
 		DbConfig.User = "postgres"
 		DbConfig.Password = token
 		DbConfig.Database = ProjectRef
+	case viaApi:
+		// Migration history is managed over HTTPS so no database credentials are needed
+		if err := LoadProjectRef(fsys); err != nil {
+			return err
+		}
+		if err := LoadConfig(fsys); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -140,7 +152,15 @@ func NewDbConfigWithPassword(ctx context.Context, projectRef string) (pgconn.Con
 		}
 		fmt.Fprintf(logger, "Resolved DNS: %v\n", conn.RemoteAddr())
 	} else if poolerConfig := utils.GetPoolerConfig(projectRef); poolerConfig != nil {
-		if len(config.Password) > 0 {
+		if len(viper.GetString("DB_JIT_ROLE")) > 0 {
+			poolerUser := poolerConfig.User
+			if err := initJitLogin(ctx, projectRef, poolerConfig); err != nil {
+				return *poolerConfig, err
+			}
+			if suffix := "." + projectRef; strings.HasSuffix(poolerUser, suffix) {
+				poolerConfig.User += suffix
+			}
+		} else if len(config.Password) > 0 {
 			fmt.Fprintln(logger, "Using database password from env var...")
 			poolerConfig.Password = config.Password
 		} else if err := initPoolerLogin(ctx, projectRef, poolerConfig); err != nil {
@@ -153,7 +173,11 @@ func NewDbConfigWithPassword(ctx context.Context, projectRef string) (pgconn.Con
 		return config, errors.Errorf("IPv6 is not supported on your current network: %w", err)
 	}
 	// Connect via direct connection
-	if len(config.Password) > 0 {
+	if len(viper.GetString("DB_JIT_ROLE")) > 0 {
+		if err := initJitLogin(ctx, projectRef, &config); err != nil {
+			return config, err
+		}
+	} else if len(config.Password) > 0 {
 		fmt.Fprintln(logger, "Using database password from env var...")
 	} else if err := initLoginRole(ctx, projectRef, &config); err != nil {
 		// Do not prompt because reading masked input is buggy on windows
@@ -177,6 +201,32 @@ func initLoginRole(ctx context.Context, projectRef string, config *pgconn.Config
 	return nil
 }
 
+// Logs in as a role granted to the current user through just-in-time access.
+func initJitLogin(ctx context.Context, projectRef string, config *pgconn.Config) error {
+	role := viper.GetString("DB_JIT_ROLE")
+	fmt.Fprintln(os.Stderr, "Authorising JIT access as role:", utils.Aqua(role))
+	// The grant is checked against the public address we connect from, which cannot be
+	// inferred from the local socket when connecting through NAT.
+	rhost := viper.GetString("DB_JIT_RHOST")
+	if len(rhost) == 0 {
+		return errors.New(request.ErrMissingRhost)
+	}
+	body := api.AuthorizeJitAccessBody{Role: role, Rhost: rhost}
+	resp, err := utils.GetSupabase().V1AuthorizeJitAccessWithResponse(ctx, projectRef, body)
+	if err != nil {
+		return errors.Errorf("failed to authorise JIT access: %w", err)
+	} else if resp.JSON200 == nil {
+		return errors.Errorf("unexpected authorise JIT access status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	token, err := utils.LoadAccessTokenFS(afero.NewOsFs())
+	if err != nil {
+		return err
+	}
+	config.User = resp.JSON200.UserRole.Role
+	config.Password = token
+	return nil
+}
+
 func initPoolerLogin(ctx context.Context, projectRef string, poolerConfig *pgconn.Config) error {
 	poolerUser := poolerConfig.User
 	if err := initLoginRole(ctx, projectRef, poolerConfig); err != nil {
@@ -205,6 +205,8 @@ type (
 		ImportMap   string `toml:"import_map" json:"importMapPath,omitempty"`
 		Entrypoint  string `toml:"entrypoint" json:"entrypointPath,omitempty"`
 		StaticFiles Glob   `toml:"static_files" json:"staticFiles,omitempty"`
+		// Secrets only visible to this function
+		Env SecretsConfig `toml:"env" json:"-"`
 	}
 
 	analytics struct {
@@ -573,6 +575,14 @@ func (c *config) load(v *viper.Viper) error {
 		secrets[strings.ToUpper(k)] = v
 	}
 	c.EdgeRuntime.Secrets = secrets
+	for slug, function := range c.Functions {
+		env := make(SecretsConfig, len(function.Env))
+		for k, v := range function.Env {
+			env[strings.ToUpper(k)] = v
+		}
+		function.Env = env
+		c.Functions[slug] = function
+	}
 	return nil
 }
 
@@ -467,6 +467,22 @@ func TestLoadFunctionImportMap(t *testing.T) {
 	})
 }
 
+func TestLoadFunctionEnv(t *testing.T) {
+	config := NewConfig()
+	fsys := fs.MapFS{
+		"supabase/config.toml": &fs.MapFile{Data: []byte(`
+		project_id = "bvikqvbczudanvggcord"
+		[functions.hello.env]
+		stripe_key = "env(TEST_STRIPE_KEY)"
+		`)},
+	}
+	t.Setenv("TEST_STRIPE_KEY", "sk_test")
+	// Run test
+	assert.NoError(t, config.Load("", fsys))
+	// Check that keys are upper cased
+	assert.Equal(t, "sk_test", config.Functions["hello"].Env["STRIPE_KEY"].Value)
+}
+
 func TestLoadFunctionErrorMessageParsing(t *testing.T) {
 	t.Run("returns error for array-style function config", func(t *testing.T) {
 		config := NewConfig()
@@ -92,8 +92,11 @@ type (
 	}
 
 	migrations struct {
-		Enabled     bool `toml:"enabled"`
-		SchemaPaths Glob `toml:"schema_paths"`
+		Enabled          bool   `toml:"enabled"`
+		SchemaPaths      Glob   `toml:"schema_paths"`
+		LockTimeout      string `toml:"lock_timeout"`
+		StatementTimeout string `toml:"statement_timeout"`
+		DiffEngine       string `toml:"diff_engine"`
 	}
 
 	seed struct {
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -17,6 +18,7 @@ import (
 	"github.com/go-errors/errors"
 	"github.com/supabase/cli/pkg/api"
 	"github.com/supabase/cli/pkg/config"
+	"github.com/supabase/cli/pkg/queue"
 )
 
 const (
@@ -48,6 +50,10 @@ func (s *EdgeRuntimeAPI) UpsertFunctions(ctx context.Context, functionConfig con
 		slugToIndex[f.Slug] = i
 	}
 	var toUpdate api.BulkUpdateFunctionBody
+	var mu sync.Mutex
+	// Bundling and uploads of each function run in a bounded worker pool
+	jq := queue.NewJobQueue(s.maxJobs)
+	var errs []error
 OUTER:
 	for slug, function := range functionConfig {
 		if !function.Enabled {
@@ -59,43 +65,64 @@ OUTER:
 				continue OUTER
 			}
 		}
-		var body bytes.Buffer
-		meta, err := s.eszip.Bundle(ctx, slug, function.Entrypoint, function.ImportMap, function.StaticFiles, &body)
-		if errors.Is(err, ErrNoDeploy) {
-			fmt.Fprintln(os.Stderr, "Skipping undeployable Function:", slug)
-			continue
-		} else if err != nil {
-			return err
-		}
-		meta.VerifyJwt = &function.VerifyJWT
-		bodyHash := sha256.Sum256(body.Bytes())
-		meta.SHA256 = hex.EncodeToString(bodyHash[:])
-		// Skip if function has not changed
-		if i, exists := slugToIndex[slug]; exists && i >= 0 &&
-			result[i].EzbrSha256 != nil && *result[i].EzbrSha256 == meta.SHA256 &&
-			result[i].VerifyJwt != nil && *result[i].VerifyJwt == function.VerifyJWT {
-			fmt.Fprintln(os.Stderr, "No change found in Function:", slug)
-			continue
+		var current *api.FunctionResponse
+		if i, exists := slugToIndex[slug]; exists {
+			current = &result[i]
 		}
-		// Update if function already exists
-		upsert := func() (api.BulkUpdateFunctionBody, error) {
-			if _, exists := slugToIndex[slug]; exists {
-				return s.updateFunction(ctx, slug, meta, bytes.NewReader(body.Bytes()))
+		deploy := func() error {
+			var body bytes.Buffer
+			meta, err := s.eszip.Bundle(ctx, slug, function.Entrypoint, function.ImportMap, function.StaticFiles, &body)
+			if errors.Is(err, ErrNoDeploy) {
+				fmt.Fprintln(os.Stderr, "Skipping undeployable Function:", slug)
+				return nil
+			} else if err != nil {
+				return err
 			}
-			return s.createFunction(ctx, slug, meta, bytes.NewReader(body.Bytes()))
-		}
-		functionSize := units.HumanSize(float64(body.Len()))
-		fmt.Fprintf(os.Stderr, "Deploying Function: %s (script size: %s)\n", slug, functionSize)
-		result, err := backoff.RetryNotifyWithData(upsert, policy, func(err error, d time.Duration) {
-			if strings.Contains(err.Error(), "Duplicated function slug") {
-				slugToIndex[slug] = -1
+			meta.VerifyJwt = &function.VerifyJWT
+			bodyHash := sha256.Sum256(body.Bytes())
+			meta.SHA256 = hex.EncodeToString(bodyHash[:])
+			// Skip if function has not changed
+			if current != nil &&
+				current.EzbrSha256 != nil && *current.EzbrSha256 == meta.SHA256 &&
+				current.VerifyJwt != nil && *current.VerifyJwt == function.VerifyJWT {
+				fmt.Fprintln(os.Stderr, "No change found in Function:", slug)
+				return nil
 			}
-		})
-		if err != nil {
-			return err
+			// Update if function already exists
+			exists := current != nil
+			upsert := func() (api.BulkUpdateFunctionBody, error) {
+				if exists {
+					return s.updateFunction(ctx, slug, meta, bytes.NewReader(body.Bytes()))
+				}
+				return s.createFunction(ctx, slug, meta, bytes.NewReader(body.Bytes()))
+			}
+			functionSize := units.HumanSize(float64(body.Len()))
+			fmt.Fprintf(os.Stderr, "Deploying Function: %s (script size: %s)\n", slug, functionSize)
+			// Backoff policy is not safe for concurrent use
+			policy := backoff.WithContext(backoff.WithMaxRetries(backoff.NewExponentialBackOff(), maxRetries), ctx)
+			resp, err := backoff.RetryNotifyWithData(upsert, policy, func(err error, d time.Duration) {
+				if strings.Contains(err.Error(), "Duplicated function slug") {
+					exists = true
+				}
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stderr, "Deployed Function:", slug)
+			mu.Lock()
+			defer mu.Unlock()
+			toUpdate = append(toUpdate, resp...)
+			return nil
 		}
-		toUpdate = append(toUpdate, result...)
-		policy.Reset()
+		errs = append(errs, jq.Put(func() error {
+			if err := deploy(); err != nil {
+				return errors.Errorf("failed to deploy Function %s: %w", slug, err)
+			}
+			return nil
+		}))
+	}
+	if err := errors.Join(append(errs, jq.Collect())...); err != nil {
+		return err
 	}
 	if len(toUpdate) > 1 {
 		if err := backoff.Retry(func() error {
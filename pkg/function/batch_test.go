@@ -30,6 +30,14 @@ func (b *MockBundler) Bundle(ctx context.Context, slug, entrypoint, importMap st
 	}, nil
 }
 
+type errBundler struct {
+	err error
+}
+
+func (b *errBundler) Bundle(ctx context.Context, slug, entrypoint, importMap string, staticFiles []string, output io.Writer) (FunctionDeployMetadata, error) {
+	return FunctionDeployMetadata{}, b.err
+}
+
 func mockClient(t *testing.T) EdgeRuntimeAPI {
 	apiClient, err := api.NewClientWithResponses(mockApiHost)
 	require.NoError(t, err)
@@ -130,6 +138,32 @@ func TestUpsertFunctions(t *testing.T) {
 		assert.Empty(t, gock.GetUnmatchedRequests())
 	})
 
+	t.Run("aggregates errors from concurrent bundles", func(t *testing.T) {
+		apiClient, err := api.NewClientWithResponses(mockApiHost)
+		require.NoError(t, err)
+		errBundle := errors.New("bundle error")
+		client := NewEdgeRuntimeAPI(mockProject, *apiClient, WithMaxJobs(2), WithBundler(&errBundler{err: errBundle}))
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(mockApiHost).
+			Get("/v1/projects/" + mockProject + "/functions").
+			Reply(http.StatusOK).
+			JSON([]api.FunctionResponse{})
+		// Run test
+		err = client.UpsertFunctions(context.Background(), config.FunctionConfig{
+			"test-a": {Enabled: true},
+			"test-b": {Enabled: true},
+			"test-c": {Enabled: true},
+		})
+		// Check error
+		assert.ErrorIs(t, err, errBundle)
+		assert.ErrorContains(t, err, "failed to deploy Function test-a: bundle error")
+		assert.ErrorContains(t, err, "failed to deploy Function test-b: bundle error")
+		assert.ErrorContains(t, err, "failed to deploy Function test-c: bundle error")
+		assert.Empty(t, gock.Pending())
+		assert.Empty(t, gock.GetUnmatchedRequests())
+	})
+
 	t.Run("retries on network failure", func(t *testing.T) {
 		// Setup mock api
 		defer gock.OffAll()
@@ -0,0 +1,165 @@
+package function
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/spf13/afero"
+	"github.com/supabase/cli/pkg/config"
+)
+
+type cachedBundler struct {
+	bundler EszipBundler
+	dir     string
+	fsys    afero.Fs
+	version string
+}
+
+// NewCachedBundler skips bundling functions whose inputs are unchanged since a previous bundle
+// was saved to dir. The cache is keyed by the bundler version, npm registry, and the content
+// of all local files in the dependency graph, so dir may be shared between CI runs. Remote
+// imports are not part of the key unless locked by deno.lock, so they should be pinned.
+func NewCachedBundler(bundler EszipBundler, dir string, fsys afero.Fs, version string) EszipBundler {
+	return &cachedBundler{
+		bundler: bundler,
+		dir:     dir,
+		fsys:    fsys,
+		version: version,
+	}
+}
+
+func (b *cachedBundler) Bundle(ctx context.Context, slug, entrypoint, importMap string, staticFiles []string, output io.Writer) (FunctionDeployMetadata, error) {
+	key, err := b.cacheKey(entrypoint, importMap, staticFiles)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "WARN: failed to compute cache key:", err)
+		return b.bundler.Bundle(ctx, slug, entrypoint, importMap, staticFiles, output)
+	}
+	cachePath := filepath.Join(b.dir, key+".eszip")
+	if data, err := afero.ReadFile(b.fsys, cachePath); err == nil {
+		fmt.Fprintln(os.Stderr, "Using cached bundle for Function:", slug)
+		if _, err := output.Write(data); err != nil {
+			return FunctionDeployMetadata{}, errors.Errorf("failed to write bundle: %w", err)
+		}
+		return NewMetadata(slug, entrypoint, importMap, staticFiles), nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintln(os.Stderr, "WARN: failed to read cached bundle:", err)
+	}
+	var buf bytes.Buffer
+	meta, err := b.bundler.Bundle(ctx, slug, entrypoint, importMap, staticFiles, &buf)
+	if err != nil {
+		return meta, err
+	}
+	if err := writeCache(cachePath, buf.Bytes(), b.fsys); err != nil {
+		fmt.Fprintln(os.Stderr, "WARN:", err)
+	}
+	if _, err := output.Write(buf.Bytes()); err != nil {
+		return meta, errors.Errorf("failed to write bundle: %w", err)
+	}
+	return meta, nil
+}
+
+// cacheKey hashes the bundler version and environment, input paths, and the content of every
+// local file resolved from the entrypoint, import map, and static files.
+func (b *cachedBundler) cacheKey(entrypoint, importMap string, staticFiles []string) (string, error) {
+	iofs := afero.NewIOFS(b.fsys)
+	files := map[string]string{}
+	hashFile := func(name string, r io.Reader) error {
+		h := sha256.New()
+		if _, err := io.Copy(h, r); err != nil {
+			return errors.Errorf("failed to hash file: %w", err)
+		}
+		files[name] = hex.EncodeToString(h.Sum(nil))
+		return nil
+	}
+	readFile := func(name string, w io.Writer) error {
+		f, err := iofs.Open(name)
+		if err != nil {
+			return errors.Errorf("failed to read file: %w", err)
+		}
+		defer f.Close()
+		return hashFile(name, io.TeeReader(f, w))
+	}
+	im := ImportMap{}
+	if imPath := toRelPath(importMap); len(importMap) > 0 {
+		if err := im.LoadAsDeno(imPath, iofs, hashFile); err != nil {
+			return "", err
+		}
+	}
+	// Lock files change the resolved versions of remote imports
+	srcPath := toRelPath(entrypoint)
+	for _, name := range []string{"deno.lock", "package.json"} {
+		lockPath := path.Join(path.Dir(srcPath), name)
+		if err := readFile(lockPath, io.Discard); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+	patterns := make(config.Glob, len(staticFiles))
+	for i, sf := range staticFiles {
+		patterns[i] = toRelPath(sf)
+	}
+	matches, err := patterns.Files(iofs)
+	if err != nil {
+		return "", err
+	}
+	for _, sfPath := range matches {
+		if err := readFile(filepath.ToSlash(sfPath), io.Discard); err != nil {
+			return "", err
+		}
+	}
+	if err := im.WalkImportPaths(srcPath, readFile); err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	fmt.Fprintln(h, b.version)
+	fmt.Fprintln(h, strings.Join(BundleFlags, " "))
+	// Same environment as passed to the bundler, which changes how npm packages are resolved
+	fmt.Fprintln(h, os.Getenv("NPM_CONFIG_REGISTRY"))
+	fmt.Fprintln(h, ShouldUsePackageJsonDiscovery(entrypoint, importMap, iofs))
+	fmt.Fprintln(h, srcPath)
+	fmt.Fprintln(h, toRelPath(importMap))
+	fmt.Fprintln(h, strings.Join(staticFiles, " "))
+	names := make([]string, 0, len(files))
+	for k := range files {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+	for _, k := range names {
+		fmt.Fprintln(h, k, files[k])
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func writeCache(cachePath string, data []byte, fsys afero.Fs) error {
+	if err := fsys.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return errors.Errorf("failed to create cache dir: %w", err)
+	}
+	// Rename is atomic so concurrent deploys never read a partial bundle
+	f, err := afero.TempFile(fsys, filepath.Dir(cachePath), filepath.Base(cachePath)+".*")
+	if err != nil {
+		return errors.Errorf("failed to create cache file: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		_ = fsys.Remove(f.Name())
+		return errors.Errorf("failed to write cache file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		_ = fsys.Remove(f.Name())
+		return errors.Errorf("failed to close cache file: %w", err)
+	}
+	if err := fsys.Rename(f.Name(), cachePath); err != nil {
+		_ = fsys.Remove(f.Name())
+		return errors.Errorf("failed to save cache file: %w", err)
+	}
+	return nil
+}
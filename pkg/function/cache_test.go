@@ -0,0 +1,140 @@
+package function
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countBundler struct {
+	calls int
+}
+
+func (b *countBundler) Bundle(ctx context.Context, slug, entrypoint, importMap string, staticFiles []string, output io.Writer) (FunctionDeployMetadata, error) {
+	b.calls++
+	_, err := output.Write([]byte("EZBR" + slug))
+	return NewMetadata(slug, entrypoint, importMap, staticFiles), err
+}
+
+func newCacheFs(t *testing.T, files map[string]string) afero.Fs {
+	fsys := afero.NewMemMapFs()
+	for name, data := range files {
+		require.NoError(t, afero.WriteFile(fsys, name, []byte(data), 0644))
+	}
+	return fsys
+}
+
+func TestCachedBundler(t *testing.T) {
+	files := map[string]string{
+		"functions/hello/index.ts":   `import { greet } from "shared/greet.ts"`,
+		"functions/hello/deno.json":  `{"imports": {"shared/": "../_shared/"}}`,
+		"functions/_shared/greet.ts": `export const greet = "hello"`,
+		"functions/hello/data.txt":   "static",
+	}
+	entrypoint := "functions/hello/index.ts"
+	importMap := "functions/hello/deno.json"
+	staticFiles := []string{"functions/hello/*.txt"}
+	cacheDir := ".cache"
+
+	t.Run("skips bundling unchanged inputs", func(t *testing.T) {
+		fsys := newCacheFs(t, files)
+		inner := &countBundler{}
+		bundler := NewCachedBundler(inner, cacheDir, fsys, "deno-2")
+		// Run test
+		var first, second bytes.Buffer
+		_, err := bundler.Bundle(context.Background(), "hello", entrypoint, importMap, staticFiles, &first)
+		require.NoError(t, err)
+		meta, err := bundler.Bundle(context.Background(), "hello", entrypoint, importMap, staticFiles, &second)
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, 1, inner.calls)
+		assert.Equal(t, first.String(), second.String())
+		assert.Equal(t, "hello", *meta.Name)
+		cached, err := afero.ReadDir(fsys, cacheDir)
+		assert.NoError(t, err)
+		assert.Len(t, cached, 1)
+	})
+
+	t.Run("rebundles on changed import", func(t *testing.T) {
+		fsys := newCacheFs(t, files)
+		inner := &countBundler{}
+		bundler := NewCachedBundler(inner, cacheDir, fsys, "deno-2")
+		_, err := bundler.Bundle(context.Background(), "hello", entrypoint, importMap, staticFiles, io.Discard)
+		require.NoError(t, err)
+		// Setup modified fs
+		require.NoError(t, afero.WriteFile(fsys, "functions/_shared/greet.ts", []byte(`export const greet = "hi"`), 0644))
+		// Run test
+		_, err = bundler.Bundle(context.Background(), "hello", entrypoint, importMap, staticFiles, io.Discard)
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, 2, inner.calls)
+	})
+
+	t.Run("rebundles on changed version", func(t *testing.T) {
+		fsys := newCacheFs(t, files)
+		inner := &countBundler{}
+		_, err := NewCachedBundler(inner, cacheDir, fsys, "deno-1").Bundle(context.Background(), "hello", entrypoint, importMap, staticFiles, io.Discard)
+		require.NoError(t, err)
+		// Run test
+		_, err = NewCachedBundler(inner, cacheDir, fsys, "deno-2").Bundle(context.Background(), "hello", entrypoint, importMap, staticFiles, io.Discard)
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, 2, inner.calls)
+	})
+
+	t.Run("rebundles on changed static file", func(t *testing.T) {
+		cb := cachedBundler{fsys: newCacheFs(t, files)}
+		key, err := cb.cacheKey(entrypoint, importMap, staticFiles)
+		require.NoError(t, err)
+		// Setup modified fs
+		require.NoError(t, afero.WriteFile(cb.fsys, "functions/hello/data.txt", []byte("changed"), 0644))
+		// Run test
+		other, err := cb.cacheKey(entrypoint, importMap, staticFiles)
+		// Check error
+		assert.NoError(t, err)
+		assert.NotEqual(t, key, other)
+	})
+
+	t.Run("rebundles on changed npm registry", func(t *testing.T) {
+		cb := cachedBundler{fsys: newCacheFs(t, files)}
+		key, err := cb.cacheKey(entrypoint, importMap, staticFiles)
+		require.NoError(t, err)
+		// Setup custom registry
+		t.Setenv("NPM_CONFIG_REGISTRY", "https://npm.example.com")
+		// Run test
+		other, err := cb.cacheKey(entrypoint, importMap, staticFiles)
+		// Check error
+		assert.NoError(t, err)
+		assert.NotEqual(t, key, other)
+	})
+
+	t.Run("rebundles on package json discovery", func(t *testing.T) {
+		fsys := newCacheFs(t, map[string]string{"functions/hello/index.ts": `import "npm:lodash"`})
+		cb := cachedBundler{fsys: fsys}
+		key, err := cb.cacheKey(entrypoint, "", nil)
+		require.NoError(t, err)
+		// Setup package json
+		require.NoError(t, afero.WriteFile(fsys, "functions/hello/package.json", []byte("{}"), 0644))
+		// Run test
+		other, err := cb.cacheKey(entrypoint, "", nil)
+		// Check error
+		assert.NoError(t, err)
+		assert.NotEqual(t, key, other)
+	})
+
+	t.Run("falls back on unwritable cache dir", func(t *testing.T) {
+		fsys := afero.NewReadOnlyFs(newCacheFs(t, files))
+		inner := &countBundler{}
+		var out bytes.Buffer
+		// Run test
+		_, err := NewCachedBundler(inner, cacheDir, fsys, "deno-2").Bundle(context.Background(), "hello", entrypoint, importMap, staticFiles, &out)
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, "EZBRhello", out.String())
+	})
+}
@@ -7,7 +7,10 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/go-errors/errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v4"
 )
 
@@ -53,25 +56,93 @@ func FindPendingMigrations(localMigrations, remoteMigrations []string) ([]string
 	return pending, nil
 }
 
-func ApplyMigrations(ctx context.Context, pending []string, conn *pgx.Conn, fsys fs.FS) error {
+type applyOption struct {
+	lockTimeout      string
+	statementTimeout string
+	policy           backoff.BackOff
+	notify           backoff.Notify
+}
+
+type ApplyOptionFunc func(*applyOption)
+
+// WithLockTimeout sets the lock_timeout of each migration, eg. "5s".
+func WithLockTimeout(timeout string) ApplyOptionFunc {
+	return func(ao *applyOption) {
+		ao.lockTimeout = timeout
+	}
+}
+
+// WithStatementTimeout sets the statement_timeout of each migration, eg. "10min".
+func WithStatementTimeout(timeout string) ApplyOptionFunc {
+	return func(ao *applyOption) {
+		ao.statementTimeout = timeout
+	}
+}
+
+// WithLockRetry retries a migration that failed to acquire a lock within lock_timeout.
+func WithLockRetry(policy backoff.BackOff, notify backoff.Notify) ApplyOptionFunc {
+	return func(ao *applyOption) {
+		ao.policy = policy
+		ao.notify = notify
+	}
+}
+
+func ApplyMigrations(ctx context.Context, pending []string, conn *pgx.Conn, fsys fs.FS, options ...ApplyOptionFunc) error {
 	if len(pending) > 0 {
 		if err := CreateMigrationTable(ctx, conn); err != nil {
 			return err
 		}
 	}
+	opts := applyOption{}
+	for _, apply := range options {
+		apply(&opts)
+	}
 	for _, path := range pending {
 		filename := filepath.Base(path)
 		fmt.Fprintf(os.Stderr, "Applying migration %s...\n", filename)
-		// Reset all connection settings that might have been modified by another statement on the same connection
-		// eg: `SELECT pg_catalog.set_config('search_path', '', false);`
-		if _, err := conn.Exec(ctx, "RESET ALL"); err != nil {
-			return errors.Errorf("failed to reset connection state: %v", err)
+		if opts.policy == nil {
+			if err := applyMigration(ctx, path, conn, fsys, opts); err != nil {
+				return err
+			}
+			continue
 		}
-		if migration, err := NewMigrationFromFile(path, fsys); err != nil {
-			return err
-		} else if err := migration.ExecBatch(ctx, conn); err != nil {
+		// Failed batches are rolled back and non-transactional files resume from their progress,
+		// so it is safe to rerun the migration
+		if err := backoff.RetryNotify(func() error {
+			err := applyMigration(ctx, path, conn, fsys, opts)
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.LockNotAvailable {
+				return err
+			} else if err != nil {
+				return &backoff.PermanentError{Err: err}
+			}
+			return nil
+		}, opts.policy, opts.notify); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+func applyMigration(ctx context.Context, path string, conn *pgx.Conn, fsys fs.FS, opts applyOption) error {
+	// Reset all connection settings that might have been modified by another statement on the same connection
+	// eg: `SELECT pg_catalog.set_config('search_path', '', false);`
+	if _, err := conn.Exec(ctx, "RESET ALL"); err != nil {
+		return errors.Errorf("failed to reset connection state: %v", err)
+	}
+	if len(opts.lockTimeout) > 0 {
+		if _, err := conn.Exec(ctx, SET_CONFIG, "lock_timeout", opts.lockTimeout); err != nil {
+			return errors.Errorf("failed to set lock timeout: %w", err)
+		}
+	}
+	if len(opts.statementTimeout) > 0 {
+		if _, err := conn.Exec(ctx, SET_CONFIG, "statement_timeout", opts.statementTimeout); err != nil {
+			return errors.Errorf("failed to set statement timeout: %w", err)
+		}
+	}
+	migration, err := NewMigrationFromFile(path, fsys)
+	if err != nil {
+		return err
+	}
+	return migration.ExecBatch(ctx, conn)
+}
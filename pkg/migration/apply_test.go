@@ -7,6 +7,7 @@ import (
 	"testing"
 	fs "testing/fstest"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/jackc/pgerrcode"
 	"github.com/stretchr/testify/assert"
 	"github.com/supabase/cli/pkg/pgtest"
@@ -112,7 +113,7 @@ func TestApplyMigrations(t *testing.T) {
 			Reply("RESET").
 			Query(testSchema).
 			Reply("CREATE SCHEMA").
-			Query(INSERT_MIGRATION_VERSION, "0", "schema", []string{testSchema}).
+			Query(INSERT_MIGRATION_VERSION, "0", "schema", []string{testSchema}, checksum(testSchema)).
 			Reply("INSERT 0 1")
 		// Run test
 		err := ApplyMigrations(context.Background(), pending, conn.MockClient(t), testMigrations)
@@ -132,7 +133,9 @@ func TestApplyMigrations(t *testing.T) {
 			Query(CREATE_VERSION_TABLE).
 			ReplyError(pgerrcode.InsufficientPrivilege, "permission denied for relation supabase_migrations").
 			Query(ADD_STATEMENTS_COLUMN).
-			Query(ADD_NAME_COLUMN)
+			Query(ADD_NAME_COLUMN).
+			Query(ADD_ROLLBACK_COLUMN).
+			Query(ADD_HASH_COLUMN)
 		// Run test
 		err := ApplyMigrations(context.Background(), pending, conn.MockClient(t), fsys)
 		// Check error
@@ -163,7 +166,7 @@ func TestApplyMigrations(t *testing.T) {
 			Reply("RESET").
 			Query(testSchema).
 			ReplyError(pgerrcode.UndefinedTable, `relation "supabase_migrations.schema_migrations" does not exist`).
-			Query(INSERT_MIGRATION_VERSION, "0", "schema", []string{testSchema}).
+			Query(INSERT_MIGRATION_VERSION, "0", "schema", []string{testSchema}, checksum(testSchema)).
 			Reply("INSERT 0 1")
 		// Run test
 		err := ApplyMigrations(context.Background(), pending, conn.MockClient(t), testMigrations)
@@ -184,6 +187,58 @@ func TestApplyMigrations(t *testing.T) {
 		assert.ErrorContains(t, err, "failed to reset connection state")
 		assert.ErrorContains(t, err, "ERROR: permission denied for RESET ALL (SQLSTATE 42501)")
 	})
+
+	t.Run("retries migration on lock timeout", func(t *testing.T) {
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		mockMigrationHistory(conn).
+			Query("RESET ALL").
+			Reply("RESET").
+			Query(SET_CONFIG, "lock_timeout", "5s").
+			Reply("SELECT 1", []any{"5s"}).
+			Query(SET_CONFIG, "statement_timeout", "10min").
+			Reply("SELECT 1", []any{"10min"}).
+			Query(testSchema).
+			ReplyError(pgerrcode.LockNotAvailable, "canceling statement due to lock timeout").
+			Query(INSERT_MIGRATION_VERSION, "0", "schema", []string{testSchema}, checksum(testSchema)).
+			Query("RESET ALL").
+			Reply("RESET").
+			Query(SET_CONFIG, "lock_timeout", "5s").
+			Reply("SELECT 1", []any{"5s"}).
+			Query(SET_CONFIG, "statement_timeout", "10min").
+			Reply("SELECT 1", []any{"10min"}).
+			Query(testSchema).
+			Reply("CREATE SCHEMA").
+			Query(INSERT_MIGRATION_VERSION, "0", "schema", []string{testSchema}, checksum(testSchema)).
+			Reply("INSERT 0 1")
+		// Run test
+		err := ApplyMigrations(context.Background(), pending, conn.MockClient(t), testMigrations,
+			WithLockTimeout("5s"),
+			WithStatementTimeout("10min"),
+			WithLockRetry(&backoff.ZeroBackOff{}, nil),
+		)
+		// Check error
+		assert.NoError(t, err)
+	})
+
+	t.Run("throws error on statement timeout", func(t *testing.T) {
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		mockMigrationHistory(conn).
+			Query("RESET ALL").
+			Reply("RESET").
+			Query(testSchema).
+			ReplyError(pgerrcode.QueryCanceled, "canceling statement due to statement timeout").
+			Query(INSERT_MIGRATION_VERSION, "0", "schema", []string{testSchema}, checksum(testSchema))
+		// Run test
+		err := ApplyMigrations(context.Background(), pending, conn.MockClient(t), testMigrations,
+			WithLockRetry(&backoff.ZeroBackOff{}, nil),
+		)
+		// Check error
+		assert.ErrorContains(t, err, "ERROR: canceling statement due to statement timeout (SQLSTATE 57014)")
+	})
 }
 
 func mockMigrationHistory(conn *pgtest.MockConn) *pgtest.MockConn {
@@ -195,6 +250,10 @@ func mockMigrationHistory(conn *pgtest.MockConn) *pgtest.MockConn {
 		Query(ADD_STATEMENTS_COLUMN).
 		Reply("ALTER TABLE").
 		Query(ADD_NAME_COLUMN).
+		Reply("ALTER TABLE").
+		Query(ADD_ROLLBACK_COLUMN).
+		Reply("ALTER TABLE").
+		Query(ADD_HASH_COLUMN).
 		Reply("ALTER TABLE")
 	return conn
 }
@@ -0,0 +1,101 @@
+package migration
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-errors/errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
+)
+
+type DriftKind string
+
+const (
+	// Applied migration file was edited locally afterwards
+	DriftModified DriftKind = "modified"
+	// Applied migration file no longer exists locally
+	DriftMissing DriftKind = "missing"
+	// Local migration file is older than the last applied migration
+	DriftReordered DriftKind = "reordered"
+)
+
+type Drift struct {
+	Version string
+	Kind    DriftKind
+}
+
+var ErrDrift = errors.New("Local migration files have drifted from the remote migration history.")
+
+// ReadRemoteHashes returns the recorded checksum of each applied migration version. Versions
+// applied before checksums were tracked are hashed from their recorded statements instead.
+func ReadRemoteHashes(ctx context.Context, conn *pgx.Conn) (map[string]string, error) {
+	// Prepare errors, ie. undefined column, are returned by rows.Err
+	rows, _ := conn.Query(ctx, SELECT_MIGRATION_HASH)
+	defer rows.Close()
+	result := map[string]string{}
+	for rows.Next() {
+		var version, hash string
+		if err := rows.Scan(&version, &hash); err != nil {
+			return nil, errors.Errorf("failed to scan migration hashes: %w", err)
+		}
+		result[version] = hash
+	}
+	if err := rows.Err(); err != nil {
+		var pgErr *pgconn.PgError
+		// History table may be missing or predate the statements column
+		if errors.As(err, &pgErr) && (pgErr.Code == pgerrcode.UndefinedTable || pgErr.Code == pgerrcode.UndefinedColumn) {
+			return nil, nil
+		}
+		return nil, errors.Errorf("failed to read migration hashes: %w", err)
+	}
+	return result, nil
+}
+
+// FindDrift compares local migration files against remote versions. Checksums are only
+// compared for remote versions with a known hash.
+func FindDrift(localMigrations, remoteMigrations []string, hashes map[string]string, fsys fs.FS) ([]Drift, error) {
+	local := make(map[string]string, len(localMigrations))
+	for _, path := range localMigrations {
+		filename := filepath.Base(path)
+		// LoadLocalMigrations guarantees a match
+		version := migrateFilePattern.FindStringSubmatch(filename)[1]
+		local[version] = path
+	}
+	var result []Drift
+	remote := make(map[string]bool, len(remoteMigrations))
+	for _, version := range remoteMigrations {
+		remote[version] = true
+		path, ok := local[version]
+		if !ok {
+			result = append(result, Drift{Version: version, Kind: DriftMissing})
+			continue
+		}
+		hash := hashes[version]
+		if len(hash) == 0 {
+			continue
+		}
+		m, err := NewMigrationFromFile(path, fsys)
+		if err != nil {
+			return nil, err
+		}
+		if m.Checksum() != hash {
+			result = append(result, Drift{Version: version, Kind: DriftModified})
+		}
+	}
+	if len(remoteMigrations) > 0 {
+		last := remoteMigrations[len(remoteMigrations)-1]
+		for version := range local {
+			if !remote[version] && version < last {
+				result = append(result, Drift{Version: version, Kind: DriftReordered})
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+	return result, nil
+}
@@ -0,0 +1,119 @@
+package migration
+
+import (
+	"context"
+	"testing"
+	fs "testing/fstest"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/cli/pkg/pgtest"
+)
+
+func checksum(statements ...string) string {
+	m := MigrationFile{Statements: statements}
+	return m.Checksum()
+}
+
+func TestFindDrift(t *testing.T) {
+	fsys := fs.MapFS{
+		"20220727064246_test.sql":  &fs.MapFile{Data: []byte("create table a()")},
+		"20220727064247_test.sql":  &fs.MapFile{Data: []byte("create table b()")},
+		"20220727064249_test.sql":  &fs.MapFile{Data: []byte("create table c()")},
+		"20220727064250_after.sql": &fs.MapFile{},
+	}
+	local := []string{
+		"20220727064246_test.sql",
+		"20220727064247_test.sql",
+		"20220727064249_test.sql",
+		"20220727064250_after.sql",
+	}
+
+	t.Run("finds modified, missing and reordered migrations", func(t *testing.T) {
+		remote := []string{"20220727064246", "20220727064248", "20220727064249"}
+		hashes := map[string]string{
+			"20220727064246": checksum("create table a()"),
+			"20220727064249": checksum("create table d()"),
+		}
+		// Run test
+		drift, err := FindDrift(local, remote, hashes, fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, []Drift{
+			{Version: "20220727064247", Kind: DriftReordered},
+			{Version: "20220727064248", Kind: DriftMissing},
+			{Version: "20220727064249", Kind: DriftModified},
+		}, drift)
+	})
+
+	t.Run("ignores unknown hashes", func(t *testing.T) {
+		remote := []string{"20220727064246", "20220727064247"}
+		// Run test
+		drift, err := FindDrift(local, remote, map[string]string{"20220727064247": ""}, fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, drift)
+	})
+
+	t.Run("throws error on missing file", func(t *testing.T) {
+		remote := []string{"20220727064246"}
+		hashes := map[string]string{"20220727064246": checksum()}
+		// Run test
+		_, err := FindDrift([]string{"20220727064246_missing.sql"}, remote, hashes, fsys)
+		// Check error
+		assert.ErrorContains(t, err, "file does not exist")
+	})
+}
+
+func TestReadRemoteHashes(t *testing.T) {
+	t.Run("reads migration hashes", func(t *testing.T) {
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(SELECT_MIGRATION_HASH).
+			Reply("SELECT 1", []any{"0", "abc"})
+		// Run test
+		hashes, err := ReadRemoteHashes(context.Background(), conn.MockClient(t))
+		// Check error
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"0": "abc"}, hashes)
+	})
+
+	t.Run("ignores missing history table", func(t *testing.T) {
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(SELECT_MIGRATION_HASH).
+			ReplyError(pgerrcode.UndefinedTable, `relation "supabase_migrations.schema_migrations" does not exist`)
+		// Run test
+		hashes, err := ReadRemoteHashes(context.Background(), conn.MockClient(t))
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, hashes)
+	})
+	t.Run("ignores missing hash column", func(t *testing.T) {
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(SELECT_MIGRATION_HASH).
+			ReplyPrepareError(pgerrcode.UndefinedColumn, `column "hash" does not exist`)
+		// Run test
+		hashes, err := ReadRemoteHashes(context.Background(), conn.MockClient(t))
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, hashes)
+	})
+
+	t.Run("throws error on prepare failure", func(t *testing.T) {
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(SELECT_MIGRATION_HASH).
+			ReplyPrepareError(pgerrcode.InsufficientPrivilege, "permission denied for schema supabase_migrations")
+		// Run test
+		_, err := ReadRemoteHashes(context.Background(), conn.MockClient(t))
+		// Check error
+		assert.ErrorContains(t, err, "failed to read migration hashes: ERROR: permission denied for schema supabase_migrations")
+	})
+}
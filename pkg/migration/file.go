@@ -24,9 +24,24 @@ type MigrationFile struct {
 	Version    string
 	Name       string
 	Statements []string
+	// Statements of the paired down script, if any
+	Rollback []string `db:"-"`
 }
 
-var migrateFilePattern = regexp.MustCompile(`^([0-9]+)_(.*)\.sql$`)
+var (
+	migrateFilePattern = regexp.MustCompile(`^([0-9]+)_(.*)\.sql$`)
+	downFilePattern    = regexp.MustCompile(`^([0-9]+)_(.*)\.down\.sql$`)
+)
+
+// DownFilePath returns the path of the down script paired with a migration file.
+func DownFilePath(path string) string {
+	return strings.TrimSuffix(path, ".sql") + ".down.sql"
+}
+
+// IsDownFile reports whether the file name matches the down script pattern.
+func IsDownFile(filename string) bool {
+	return downFilePattern.MatchString(filepath.Base(filename))
+}
 
 func NewMigrationFromFile(path string, fsys fs.FS) (*MigrationFile, error) {
 	lines, err := parseFile(path, fsys)
@@ -34,6 +49,15 @@ func NewMigrationFromFile(path string, fsys fs.FS) (*MigrationFile, error) {
 		return nil, err
 	}
 	file := MigrationFile{Statements: lines}
+	// Load paired down script if it exists
+	downPath := DownFilePath(path)
+	if _, err := fs.Stat(fsys, downPath); err == nil {
+		if file.Rollback, err = parseFile(downPath, fsys); err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, errors.Errorf("failed to stat down script: %w", err)
+	}
 	// Parse version from file name
 	filename := filepath.Base(path)
 	matches := migrateFilePattern.FindStringSubmatch(filename)
@@ -70,6 +94,9 @@ func NewMigrationFromReader(sql io.Reader) (*MigrationFile, error) {
 }
 
 func (m *MigrationFile) ExecBatch(ctx context.Context, conn *pgx.Conn) error {
+	if !m.IsTransactional() {
+		return m.execSegments(ctx, conn)
+	}
 	// Batch migration commands, without using statement cache
 	batch := &pgconn.Batch{}
 	for _, line := range m.Statements {
@@ -80,29 +107,39 @@ func (m *MigrationFile) ExecBatch(ctx context.Context, conn *pgx.Conn) error {
 		if err := m.insertVersionSQL(conn, batch); err != nil {
 			return err
 		}
+		if len(m.Rollback) > 0 {
+			if err := m.updateRollbackSQL(conn, batch); err != nil {
+				return err
+			}
+		}
 	}
 	// ExecBatch is implicitly transactional
 	if result, err := conn.PgConn().ExecBatch(ctx, batch).ReadAll(); err != nil {
-		// Defaults to printing the last statement on error
-		stat := INSERT_MIGRATION_VERSION
-		i := len(result)
-		if i < len(m.Statements) {
-			stat = m.Statements[i]
-		}
-		var msg []string
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) {
-			stat = markError(stat, int(pgErr.Position))
-			if len(pgErr.Detail) > 0 {
-				msg = append(msg, pgErr.Detail)
-			}
-		}
-		msg = append(msg, fmt.Sprintf("At statement: %d", i), stat)
-		return errors.Errorf("%w\n%s", err, strings.Join(msg, "\n"))
+		return m.batchError(err, len(result), len(m.Statements), INSERT_MIGRATION_VERSION)
 	}
 	return nil
 }
 
+// batchError annotates err with the statement at index i, or the fallback statement if the
+// batch failed after executing all statements up to end.
+func (m *MigrationFile) batchError(err error, i, end int, fallback string) error {
+	// Defaults to printing the last statement on error
+	stat := fallback
+	if i < end {
+		stat = m.Statements[i]
+	}
+	var msg []string
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		stat = markError(stat, int(pgErr.Position))
+		if len(pgErr.Detail) > 0 {
+			msg = append(msg, pgErr.Detail)
+		}
+	}
+	msg = append(msg, fmt.Sprintf("At statement: %d", i), stat)
+	return errors.Errorf("%w\n%s", err, strings.Join(msg, "\n"))
+}
+
 func markError(stat string, pos int) string {
 	lines := strings.Split(stat, "\n")
 	for j, r := range lines {
@@ -121,9 +158,45 @@ func markError(stat string, pos int) string {
 }
 
 func (m *MigrationFile) insertVersionSQL(conn *pgx.Conn, batch *pgconn.Batch) error {
+	encoded, valueFormat, err := encodeTextArray(conn, m.Statements)
+	if err != nil {
+		return err
+	}
+	batch.ExecParams(
+		INSERT_MIGRATION_VERSION,
+		[][]byte{[]byte(m.Version), []byte(m.Name), encoded, []byte(m.Checksum())},
+		[]uint32{pgtype.TextOID, pgtype.TextOID, pgtype.TextArrayOID, pgtype.TextOID},
+		[]int16{pgtype.TextFormatCode, pgtype.TextFormatCode, valueFormat, pgtype.TextFormatCode},
+		nil,
+	)
+	return nil
+}
+
+// Checksum returns the hex encoded sha256 digest of all statements joined into a single script.
+func (m *MigrationFile) Checksum() string {
+	digest := sha256.Sum256([]byte(m.String()))
+	return hex.EncodeToString(digest[:])
+}
+
+func (m *MigrationFile) updateRollbackSQL(conn *pgx.Conn, batch *pgconn.Batch) error {
+	encoded, valueFormat, err := encodeTextArray(conn, m.Rollback)
+	if err != nil {
+		return err
+	}
+	batch.ExecParams(
+		UPDATE_MIGRATION_ROLLBACK,
+		[][]byte{[]byte(m.Version), encoded},
+		[]uint32{pgtype.TextOID, pgtype.TextArrayOID},
+		[]int16{pgtype.TextFormatCode, valueFormat},
+		nil,
+	)
+	return nil
+}
+
+func encodeTextArray(conn *pgx.Conn, values []string) ([]byte, int16, error) {
 	value := pgtype.TextArray{}
-	if err := value.Set(m.Statements); err != nil {
-		return errors.Errorf("failed to set text array: %w", err)
+	if err := value.Set(values); err != nil {
+		return nil, 0, errors.Errorf("failed to set text array: %w", err)
 	}
 	ci := conn.ConnInfo()
 	var err error
@@ -137,16 +210,9 @@ func (m *MigrationFile) insertVersionSQL(conn *pgx.Conn, batch *pgconn.Batch) er
 		valueFormat = pgtype.BinaryFormatCode
 	}
 	if err != nil {
-		return errors.Errorf("failed to encode binary: %w", err)
+		return nil, 0, errors.Errorf("failed to encode binary: %w", err)
 	}
-	batch.ExecParams(
-		INSERT_MIGRATION_VERSION,
-		[][]byte{[]byte(m.Version), []byte(m.Name), encoded},
-		[]uint32{pgtype.TextOID, pgtype.TextOID, pgtype.TextArrayOID},
-		[]int16{pgtype.TextFormatCode, pgtype.TextFormatCode, valueFormat},
-		nil,
-	)
-	return nil
+	return encoded, valueFormat, nil
 }
 
 type SeedFile struct {
@@ -30,6 +30,42 @@ func TestMigrationFile(t *testing.T) {
 		assert.Equal(t, "20220727064247", migration.Version)
 	})
 
+	t.Run("new from file loads paired down script", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := fs.MapFS{
+			"20220727064247_create_table.sql":      &fs.MapFile{Data: []byte("create table a (id int)")},
+			"20220727064247_create_table.down.sql": &fs.MapFile{Data: []byte("drop table a")},
+		}
+		// Run test
+		migration, err := NewMigrationFromFile("20220727064247_create_table.sql", fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"create table a (id int)"}, migration.Statements)
+		assert.Equal(t, []string{"drop table a"}, migration.Rollback)
+		assert.Equal(t, "create_table", migration.Name)
+	})
+
+	t.Run("records down script in history", func(t *testing.T) {
+		migration := MigrationFile{
+			Statements: []string{"create table a (id int)"},
+			Rollback:   []string{"drop table a"},
+			Version:    "0",
+		}
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(migration.Statements[0]).
+			Reply("CREATE TABLE").
+			Query(INSERT_MIGRATION_VERSION, "0", "", migration.Statements, migration.Checksum()).
+			Reply("INSERT 0 1").
+			Query(UPDATE_MIGRATION_ROLLBACK, "0", migration.Rollback).
+			Reply("UPDATE 1")
+		// Run test
+		err := migration.ExecBatch(context.Background(), conn.MockClient(t))
+		// Check error
+		assert.NoError(t, err)
+	})
+
 	t.Run("new from reader errors on max token", func(t *testing.T) {
 		viper.Reset()
 		sql := "\tBEGIN; " + strings.Repeat("a", parser.MaxScannerCapacity)
@@ -51,7 +87,7 @@ func TestMigrationFile(t *testing.T) {
 		defer conn.Close(t)
 		conn.Query(migration.Statements[0]).
 			Reply("CREATE SCHEMA").
-			Query(INSERT_MIGRATION_VERSION, "0", "", migration.Statements).
+			Query(INSERT_MIGRATION_VERSION, "0", "", migration.Statements, migration.Checksum()).
 			Reply("INSERT 0 1")
 		// Run test
 		err := migration.ExecBatch(context.Background(), conn.MockClient(t))
@@ -69,7 +105,7 @@ func TestMigrationFile(t *testing.T) {
 		defer conn.Close(t)
 		conn.Query(migration.Statements[0]).
 			ReplyError(pgerrcode.DuplicateSchema, `schema "public" already exists`).
-			Query(INSERT_MIGRATION_VERSION, "0", "", migration.Statements).
+			Query(INSERT_MIGRATION_VERSION, "0", "", migration.Statements, migration.Checksum()).
 			Reply("INSERT 0 1")
 		// Run test
 		err := migration.ExecBatch(context.Background(), conn.MockClient(t))
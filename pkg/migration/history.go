@@ -10,21 +10,31 @@ import (
 )
 
 const (
-	SET_LOCK_TIMEOUT         = "SET lock_timeout = '4s'"
-	CREATE_VERSION_SCHEMA    = "CREATE SCHEMA IF NOT EXISTS supabase_migrations"
-	CREATE_VERSION_TABLE     = "CREATE TABLE IF NOT EXISTS supabase_migrations.schema_migrations (version text NOT NULL PRIMARY KEY)"
-	ADD_STATEMENTS_COLUMN    = "ALTER TABLE supabase_migrations.schema_migrations ADD COLUMN IF NOT EXISTS statements text[]"
-	ADD_NAME_COLUMN          = "ALTER TABLE supabase_migrations.schema_migrations ADD COLUMN IF NOT EXISTS name text"
-	INSERT_MIGRATION_VERSION = "INSERT INTO supabase_migrations.schema_migrations(version, name, statements) VALUES($1, $2, $3)"
-	UPSERT_MIGRATION_VERSION = INSERT_MIGRATION_VERSION + " ON CONFLICT (version) DO UPDATE SET name = EXCLUDED.name, statements = EXCLUDED.statements"
-	DELETE_MIGRATION_VERSION = "DELETE FROM supabase_migrations.schema_migrations WHERE version = ANY($1)"
-	DELETE_MIGRATION_BEFORE  = "DELETE FROM supabase_migrations.schema_migrations WHERE version <= $1"
-	TRUNCATE_VERSION_TABLE   = "TRUNCATE supabase_migrations.schema_migrations"
-	SELECT_VERSION_TABLE     = "SELECT version, coalesce(name, '') as name, statements FROM supabase_migrations.schema_migrations"
-	LIST_MIGRATION_VERSION   = "SELECT version FROM supabase_migrations.schema_migrations ORDER BY version"
-	CREATE_SEED_TABLE        = "CREATE TABLE IF NOT EXISTS supabase_migrations.seed_files (path text NOT NULL PRIMARY KEY, hash text NOT NULL)"
-	UPSERT_SEED_FILE         = "INSERT INTO supabase_migrations.seed_files(path, hash) VALUES($1, $2) ON CONFLICT (path) DO UPDATE SET hash = EXCLUDED.hash"
-	SELECT_SEED_TABLE        = "SELECT path, hash FROM supabase_migrations.seed_files"
+	SET_LOCK_TIMEOUT          = "SET lock_timeout = '4s'"
+	SET_CONFIG                = "SELECT pg_catalog.set_config($1, $2, false)"
+	CREATE_VERSION_SCHEMA     = "CREATE SCHEMA IF NOT EXISTS supabase_migrations"
+	CREATE_VERSION_TABLE      = "CREATE TABLE IF NOT EXISTS supabase_migrations.schema_migrations (version text NOT NULL PRIMARY KEY)"
+	ADD_STATEMENTS_COLUMN     = "ALTER TABLE supabase_migrations.schema_migrations ADD COLUMN IF NOT EXISTS statements text[]"
+	ADD_NAME_COLUMN           = "ALTER TABLE supabase_migrations.schema_migrations ADD COLUMN IF NOT EXISTS name text"
+	ADD_ROLLBACK_COLUMN       = "ALTER TABLE supabase_migrations.schema_migrations ADD COLUMN IF NOT EXISTS rollback text[]"
+	ADD_HASH_COLUMN           = "ALTER TABLE supabase_migrations.schema_migrations ADD COLUMN IF NOT EXISTS hash text"
+	INSERT_MIGRATION_VERSION  = "INSERT INTO supabase_migrations.schema_migrations(version, name, statements, hash) VALUES($1, $2, $3, $4)"
+	UPSERT_MIGRATION_VERSION  = INSERT_MIGRATION_VERSION + " ON CONFLICT (version) DO UPDATE SET name = EXCLUDED.name, statements = EXCLUDED.statements, hash = EXCLUDED.hash"
+	UPDATE_MIGRATION_ROLLBACK = "UPDATE supabase_migrations.schema_migrations SET rollback = $2 WHERE version = $1"
+	SELECT_MIGRATION_ROLLBACK = "SELECT version, coalesce(name, '') as name, coalesce(rollback, '{}') as rollback FROM supabase_migrations.schema_migrations WHERE version = ANY($1) ORDER BY version DESC"
+	DELETE_MIGRATION_VERSION  = "DELETE FROM supabase_migrations.schema_migrations WHERE version = ANY($1)"
+	DELETE_MIGRATION_BEFORE   = "DELETE FROM supabase_migrations.schema_migrations WHERE version <= $1"
+	TRUNCATE_VERSION_TABLE    = "TRUNCATE supabase_migrations.schema_migrations"
+	SELECT_VERSION_TABLE      = "SELECT version, coalesce(name, '') as name, statements FROM supabase_migrations.schema_migrations"
+	LIST_MIGRATION_VERSION    = "SELECT version FROM supabase_migrations.schema_migrations ORDER BY version"
+	SELECT_MIGRATION_HASH     = "SELECT version, coalesce(hash, (SELECT encode(sha256(convert_to(string_agg(s || E';\\n', '' ORDER BY i), 'UTF8')), 'hex') FROM unnest(statements) WITH ORDINALITY AS t(s, i)), '') AS hash FROM supabase_migrations.schema_migrations"
+	CREATE_PROGRESS_TABLE     = "CREATE TABLE IF NOT EXISTS supabase_migrations.schema_migration_progress (version text NOT NULL PRIMARY KEY, hash text NOT NULL, applied int NOT NULL, running boolean NOT NULL DEFAULT false)"
+	SELECT_MIGRATION_PROGRESS = "SELECT applied, hash, running FROM supabase_migrations.schema_migration_progress WHERE version = $1"
+	UPSERT_MIGRATION_PROGRESS = "INSERT INTO supabase_migrations.schema_migration_progress(version, hash, applied, running) VALUES($1, $2, $3, $4) ON CONFLICT (version) DO UPDATE SET hash = EXCLUDED.hash, applied = EXCLUDED.applied, running = EXCLUDED.running"
+	DELETE_MIGRATION_PROGRESS = "DELETE FROM supabase_migrations.schema_migration_progress WHERE version = $1"
+	CREATE_SEED_TABLE         = "CREATE TABLE IF NOT EXISTS supabase_migrations.seed_files (path text NOT NULL PRIMARY KEY, hash text NOT NULL)"
+	UPSERT_SEED_FILE          = "INSERT INTO supabase_migrations.seed_files(path, hash) VALUES($1, $2) ON CONFLICT (path) DO UPDATE SET hash = EXCLUDED.hash"
+	SELECT_SEED_TABLE         = "SELECT path, hash FROM supabase_migrations.seed_files"
 )
 
 // TODO: support overriding `supabase_migrations.schema_migrations` with user defined <schema>.<table>
@@ -37,6 +47,8 @@ func CreateMigrationTable(ctx context.Context, conn *pgx.Conn) error {
 	batch.ExecParams(CREATE_VERSION_TABLE, nil, nil, nil, nil)
 	batch.ExecParams(ADD_STATEMENTS_COLUMN, nil, nil, nil, nil)
 	batch.ExecParams(ADD_NAME_COLUMN, nil, nil, nil, nil)
+	batch.ExecParams(ADD_ROLLBACK_COLUMN, nil, nil, nil, nil)
+	batch.ExecParams(ADD_HASH_COLUMN, nil, nil, nil, nil)
 	if _, err := conn.PgConn().ExecBatch(ctx, &batch).ReadAll(); err != nil {
 		return errors.Errorf("failed to create migration table: %w", err)
 	}
@@ -71,8 +71,9 @@ func TestLocalMigrations(t *testing.T) {
 	t.Run("ignores outdated and invalid files", func(t *testing.T) {
 		// Setup in-memory fs
 		fsys := fs.MapFS{
-			"20211208000000_init.sql":   &fs.MapFile{},
-			"20211208000001_invalid.ts": &fs.MapFile{},
+			"20211208000000_init.sql":      &fs.MapFile{},
+			"20211208000001_invalid.ts":    &fs.MapFile{},
+			"20211208000002_test.down.sql": &fs.MapFile{},
 		}
 		// Run test
 		versions, err := ListLocalMigrations(".", fsys)
@@ -0,0 +1,223 @@
+package migration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/jackc/pgx/v4"
+	"github.com/supabase/cli/pkg/api"
+)
+
+var (
+	ErrRevertViaApi      = errors.New("Migrations cannot be marked as reverted through the Management API.")
+	ErrUnrecordedVersion = errors.New("Management API recorded a different version than the local migration.")
+)
+
+// MigrationHistory lists, applies and repairs migrations on a remote database.
+type MigrationHistory interface {
+	ListRemoteMigrations(ctx context.Context) ([]string, error)
+	ApplyMigrations(ctx context.Context, pending []string, fsys fs.FS) error
+	// MarkApplied records migrations in the history table without running their statements.
+	MarkApplied(ctx context.Context, migrations []*MigrationFile) error
+	// MarkReverted removes versions from the history table.
+	MarkReverted(ctx context.Context, versions []string) error
+}
+
+type pgHistory struct {
+	conn *pgx.Conn
+}
+
+// NewPgHistory tracks migrations in the history table over a direct database connection.
+func NewPgHistory(conn *pgx.Conn) MigrationHistory {
+	return pgHistory{conn: conn}
+}
+
+func (h pgHistory) ListRemoteMigrations(ctx context.Context) ([]string, error) {
+	return ListRemoteMigrations(ctx, h.conn)
+}
+
+func (h pgHistory) ApplyMigrations(ctx context.Context, pending []string, fsys fs.FS) error {
+	return ApplyMigrations(ctx, pending, h.conn, fsys)
+}
+
+func (h pgHistory) MarkApplied(ctx context.Context, migrations []*MigrationFile) error {
+	if err := CreateMigrationTable(ctx, h.conn); err != nil {
+		return err
+	}
+	batch := &pgx.Batch{}
+	for _, m := range migrations {
+		batch.Queue(UPSERT_MIGRATION_VERSION, m.Version, m.Name, m.Statements, m.Checksum())
+		if len(m.Rollback) > 0 {
+			batch.Queue(UPDATE_MIGRATION_ROLLBACK, m.Version, m.Rollback)
+		}
+	}
+	if err := h.conn.SendBatch(ctx, batch).Close(); err != nil {
+		return errors.Errorf("failed to update migration table: %w", err)
+	}
+	return nil
+}
+
+func (h pgHistory) MarkReverted(ctx context.Context, versions []string) error {
+	if err := CreateMigrationTable(ctx, h.conn); err != nil {
+		return err
+	}
+	if _, err := h.conn.Exec(ctx, DELETE_MIGRATION_VERSION, versions); err != nil {
+		return errors.Errorf("failed to update migration table: %w", err)
+	}
+	return nil
+}
+
+type apiHistory struct {
+	project string
+	client  api.ClientWithResponses
+}
+
+// NewApiHistory tracks migrations of a hosted project through the Management API.
+func NewApiHistory(project string, client api.ClientWithResponses) MigrationHistory {
+	return apiHistory{project: project, client: client}
+}
+
+func (h apiHistory) ListRemoteMigrations(ctx context.Context) ([]string, error) {
+	resp, err := h.client.V1ListMigrationHistoryWithResponse(ctx, h.project)
+	if err != nil {
+		return nil, errors.Errorf("failed to list migrations: %w", err)
+	} else if resp.JSON200 == nil {
+		return nil, errors.Errorf("unexpected list migrations status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	var versions []string
+	for _, m := range *resp.JSON200 {
+		versions = append(versions, m.Version)
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+// The published schema omits version, so every write is checked against the history afterwards
+// in case the API assigns one from its own clock.
+type migrationBody struct {
+	api.V1CreateMigrationBody
+	Version string `json:"version"`
+}
+
+func newMigrationBody(migration *MigrationFile) migrationBody {
+	body := migrationBody{
+		V1CreateMigrationBody: api.V1CreateMigrationBody{
+			Name:  &migration.Name,
+			Query: migration.String(),
+		},
+		Version: migration.Version,
+	}
+	if len(migration.Rollback) > 0 {
+		rollback := joinStatements(migration.Rollback)
+		body.Rollback = &rollback
+	}
+	return body
+}
+
+func (h apiHistory) ApplyMigrations(ctx context.Context, pending []string, fsys fs.FS) error {
+	for _, path := range pending {
+		filename := filepath.Base(path)
+		fmt.Fprintf(os.Stderr, "Applying migration %s...\n", filename)
+		migration, err := NewMigrationFromFile(path, fsys)
+		if err != nil {
+			return err
+		} else if !migration.IsTransactional() {
+			return errors.Errorf("%w: %s", ErrNoTransactionViaApi, filename)
+		}
+		data, err := json.Marshal(newMigrationBody(migration))
+		if err != nil {
+			return errors.Errorf("failed to encode migration: %w", err)
+		}
+		// Keying on version ensures retries do not apply the same migration twice
+		params := api.V1ApplyAMigrationParams{IdempotencyKey: &migration.Version}
+		resp, err := h.client.V1ApplyAMigrationWithBodyWithResponse(ctx, h.project, &params, "application/json", bytes.NewReader(data))
+		if err != nil {
+			return errors.Errorf("failed to apply migration: %w", err)
+		} else if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+			return errors.Errorf("unexpected apply migration status %d: %s", resp.StatusCode(), string(resp.Body))
+		}
+		// Stop before the next push would apply the same migration again under a new version
+		if err := h.checkRecorded(ctx, migration.Version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h apiHistory) checkRecorded(ctx context.Context, version string) error {
+	remote, err := h.ListRemoteMigrations(ctx)
+	if err != nil {
+		return err
+	}
+	if _, found := slices.BinarySearch(remote, version); !found {
+		return errors.Errorf("%w: %s", ErrUnrecordedVersion, version)
+	}
+	return nil
+}
+
+func (h apiHistory) MarkApplied(ctx context.Context, migrations []*MigrationFile) error {
+	remote, err := h.ListRemoteMigrations(ctx)
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		body := newMigrationBody(m)
+		if _, found := slices.BinarySearch(remote, m.Version); found {
+			patch := api.V1PatchMigrationBody{Name: body.Name, Rollback: body.Rollback}
+			resp, err := h.client.V1PatchAMigrationWithResponse(ctx, h.project, m.Version, patch)
+			if err != nil {
+				return errors.Errorf("failed to update migration: %w", err)
+			} else if resp.StatusCode() != http.StatusOK {
+				return errors.Errorf("unexpected update migration status %d: %s", resp.StatusCode(), string(resp.Body))
+			}
+			continue
+		}
+		data, err := json.Marshal(body)
+		if err != nil {
+			return errors.Errorf("failed to encode migration: %w", err)
+		}
+		params := api.V1UpsertAMigrationParams{IdempotencyKey: &m.Version}
+		resp, err := h.client.V1UpsertAMigrationWithBodyWithResponse(ctx, h.project, &params, "application/json", bytes.NewReader(data))
+		if err != nil {
+			return errors.Errorf("failed to upsert migration: %w", err)
+		} else if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+			return errors.Errorf("unexpected upsert migration status %d: %s", resp.StatusCode(), string(resp.Body))
+		}
+		if err := h.checkRecorded(ctx, m.Version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarkReverted is refused because the only API endpoint that deletes history rows also runs their rollback scripts.
+func (h apiHistory) MarkReverted(ctx context.Context, versions []string) error {
+	if len(versions) == 0 {
+		return nil
+	}
+	return errors.Errorf("%w: %s", ErrRevertViaApi, strings.Join(versions, ", "))
+}
+
+// String joins all statements back into a single script.
+func (m *MigrationFile) String() string {
+	return joinStatements(m.Statements)
+}
+
+func joinStatements(lines []string) string {
+	var sql strings.Builder
+	for _, line := range lines {
+		sql.WriteString(line)
+		sql.WriteString(";\n")
+	}
+	return sql.String()
+}
@@ -0,0 +1,150 @@
+package migration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	fs "testing/fstest"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/cli/pkg/api"
+	"github.com/supabase/cli/pkg/cast"
+)
+
+const (
+	mockApiHost = "https://api.supabase.com"
+	mockProject = "test-project"
+)
+
+func TestApiHistory(t *testing.T) {
+	apiClient, err := api.NewClientWithResponses(mockApiHost)
+	require.NoError(t, err)
+	history := NewApiHistory(mockProject, *apiClient)
+
+	t.Run("lists remote migrations in order", func(t *testing.T) {
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(mockApiHost).
+			Get("/v1/projects/" + mockProject + "/database/migrations").
+			Reply(http.StatusOK).
+			JSON([]map[string]string{{"version": "20240102"}, {"version": "20240101"}})
+		// Run test
+		versions, err := history.ListRemoteMigrations(context.Background())
+		// Check error
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"20240101", "20240102"}, versions)
+		assert.Empty(t, gock.Pending())
+	})
+
+	t.Run("applies migration keyed by version", func(t *testing.T) {
+		fsys := fs.MapFS{
+			"0_test.sql": &fs.MapFile{Data: []byte("create table a (id int);\ncreate table b (id int);")},
+		}
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(mockApiHost).
+			Post("/v1/projects/"+mockProject+"/database/migrations").
+			MatchHeader("Idempotency-Key", "0").
+			JSON(migrationBody{
+				V1CreateMigrationBody: api.V1CreateMigrationBody{
+					Name:  cast.Ptr("test"),
+					Query: "create table a (id int);\ncreate table b (id int);\n",
+				},
+				Version: "0",
+			}).
+			Reply(http.StatusOK)
+		gock.New(mockApiHost).
+			Get("/v1/projects/" + mockProject + "/database/migrations").
+			Reply(http.StatusOK).
+			JSON([]map[string]string{{"version": "0"}})
+		// Run test
+		err := history.ApplyMigrations(context.Background(), []string{"0_test.sql"}, fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, gock.Pending())
+	})
+
+	t.Run("throws error on unrecorded version", func(t *testing.T) {
+		fsys := fs.MapFS{
+			"0_test.sql": &fs.MapFile{Data: []byte("select 1")},
+		}
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(mockApiHost).
+			Post("/v1/projects/" + mockProject + "/database/migrations").
+			Reply(http.StatusOK)
+		gock.New(mockApiHost).
+			Get("/v1/projects/" + mockProject + "/database/migrations").
+			Reply(http.StatusOK).
+			JSON([]map[string]string{{"version": "20240101000000"}})
+		// Run test
+		err := history.ApplyMigrations(context.Background(), []string{"0_test.sql", "1_next.sql"}, fsys)
+		// Check error
+		assert.ErrorIs(t, err, ErrUnrecordedVersion)
+		assert.Empty(t, gock.Pending())
+	})
+
+	t.Run("throws error on apply failure", func(t *testing.T) {
+		fsys := fs.MapFS{
+			"0_test.sql": &fs.MapFile{Data: []byte("select 1")},
+		}
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(mockApiHost).
+			Post("/v1/projects/" + mockProject + "/database/migrations").
+			Reply(http.StatusBadRequest).
+			JSON(map[string]string{"message": "syntax error"})
+		// Run test
+		err := history.ApplyMigrations(context.Background(), []string{"0_test.sql"}, fsys)
+		// Check error
+		assert.ErrorContains(t, err, "unexpected apply migration status 400:")
+		assert.Empty(t, gock.Pending())
+	})
+
+	t.Run("marks new and existing versions applied", func(t *testing.T) {
+		migrations := []*MigrationFile{
+			{Version: "20240101", Name: "init", Statements: []string{"select 1"}},
+			{Version: "20240102", Name: "next", Statements: []string{"select 2"}, Rollback: []string{"select 3"}},
+		}
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(mockApiHost).
+			Get("/v1/projects/" + mockProject + "/database/migrations").
+			Reply(http.StatusOK).
+			JSON([]map[string]string{{"version": "20240101"}})
+		gock.New(mockApiHost).
+			Patch("/v1/projects/" + mockProject + "/database/migrations/20240101").
+			JSON(api.V1PatchMigrationBody{Name: cast.Ptr("init")}).
+			Reply(http.StatusOK)
+		gock.New(mockApiHost).
+			Put("/v1/projects/"+mockProject+"/database/migrations").
+			MatchHeader("Idempotency-Key", "20240102").
+			JSON(migrationBody{
+				V1CreateMigrationBody: api.V1CreateMigrationBody{
+					Name:     cast.Ptr("next"),
+					Query:    "select 2;\n",
+					Rollback: cast.Ptr("select 3;\n"),
+				},
+				Version: "20240102",
+			}).
+			Reply(http.StatusOK)
+		gock.New(mockApiHost).
+			Get("/v1/projects/" + mockProject + "/database/migrations").
+			Reply(http.StatusOK).
+			JSON([]map[string]string{{"version": "20240101"}, {"version": "20240102"}})
+		// Run test
+		err := history.MarkApplied(context.Background(), migrations)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, gock.Pending())
+	})
+
+	t.Run("throws error on marking reverted", func(t *testing.T) {
+		// Run test
+		err := history.MarkReverted(context.Background(), []string{"20240101"})
+		// Check error
+		assert.ErrorIs(t, err, ErrRevertViaApi)
+	})
+}
@@ -0,0 +1,131 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+)
+
+var ErrMissingRollback = errors.New("Down scripts not found in migration history table.")
+
+// ReadRollbackScripts returns the recorded down scripts of the given versions, latest version first.
+func ReadRollbackScripts(ctx context.Context, versions []string, conn *pgx.Conn) ([]MigrationFile, error) {
+	rows, err := conn.Query(ctx, SELECT_MIGRATION_ROLLBACK, versions)
+	if isUndefinedColumn(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Errorf("failed to read rollback scripts: %w", err)
+	}
+	defer rows.Close()
+	var result []MigrationFile
+	for rows.Next() {
+		var m MigrationFile
+		if err := rows.Scan(&m.Version, &m.Name, &m.Rollback); err != nil {
+			return nil, errors.Errorf("failed to scan rollback scripts: %w", err)
+		}
+		result = append(result, m)
+	}
+	if err := rows.Err(); isUndefinedColumn(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Errorf("failed to read rollback scripts: %w", err)
+	}
+	return result, nil
+}
+
+// History tables created by older versions have no rollback column
+func isUndefinedColumn(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UndefinedColumn
+}
+
+// FindMissingRollbacks returns the versions that have no recorded down script.
+func FindMissingRollbacks(versions []string, scripts []MigrationFile) []string {
+	recorded := make(map[string]bool, len(scripts))
+	for _, m := range scripts {
+		recorded[m.Version] = len(m.Rollback) > 0
+	}
+	var missing []string
+	for _, v := range versions {
+		if !recorded[v] {
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
+
+// RollbackMigrations runs the down scripts in the given order and removes their versions
+// from the migration history table, all within a single transaction. Down scripts with
+// NoTransactionDirective are instead reverted one migration at a time.
+func RollbackMigrations(ctx context.Context, scripts []MigrationFile, conn *pgx.Conn) error {
+	for _, m := range scripts {
+		down := MigrationFile{Statements: m.Rollback}
+		if !down.IsTransactional() {
+			return rollbackEach(ctx, scripts, conn)
+		}
+	}
+	var versions []string
+	var stats []string
+	batch := pgconn.Batch{}
+	for _, m := range scripts {
+		fmt.Fprintf(os.Stderr, "Reverting migration %s_%s...\n", m.Version, m.Name)
+		for _, line := range m.Rollback {
+			batch.ExecParams(line, nil, nil, nil, nil)
+			stats = append(stats, line)
+		}
+		versions = append(versions, m.Version)
+	}
+	encoded, valueFormat, err := encodeTextArray(conn, versions)
+	if err != nil {
+		return err
+	}
+	batch.ExecParams(
+		DELETE_MIGRATION_VERSION,
+		[][]byte{encoded},
+		[]uint32{pgtype.TextArrayOID},
+		[]int16{valueFormat},
+		nil,
+	)
+	// ExecBatch is implicitly transactional
+	if result, err := conn.PgConn().ExecBatch(ctx, &batch).ReadAll(); err != nil {
+		stat := DELETE_MIGRATION_VERSION
+		i := len(result)
+		if i < len(stats) {
+			stat = stats[i]
+		}
+		var msg []string
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			stat = markError(stat, int(pgErr.Position))
+			if len(pgErr.Detail) > 0 {
+				msg = append(msg, pgErr.Detail)
+			}
+		}
+		msg = append(msg, fmt.Sprintf("At statement: %d", i), stat)
+		return errors.Errorf("%w\n%s", err, strings.Join(msg, "\n"))
+	}
+	return nil
+}
+
+// rollbackEach runs each down script through the same segments as forward migrations, so
+// statements outside a transaction are not wrapped in the batch of other statements.
+func rollbackEach(ctx context.Context, scripts []MigrationFile, conn *pgx.Conn) error {
+	for _, m := range scripts {
+		fmt.Fprintf(os.Stderr, "Reverting migration %s_%s...\n", m.Version, m.Name)
+		down := MigrationFile{Statements: m.Rollback}
+		if err := down.ExecBatch(ctx, conn); err != nil {
+			return errors.Errorf("%w\nMigration %s may be partially reverted because some statements ran outside a transaction.", err, m.Version)
+		}
+		if _, err := conn.Exec(ctx, DELETE_MIGRATION_VERSION, []string{m.Version}); err != nil {
+			return errors.Errorf("failed to update migration table: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+)
+
+// NoTransactionDirective marks the next statement to run outside a transaction block. When it
+// precedes the first statement of a file, every statement in the file runs on its own. Such
+// statements must be idempotent because they are retried on resume if the process exits
+// before their progress is recorded.
+const NoTransactionDirective = "-- supabase:no-transaction"
+
+var (
+	ErrNoTransactionViaApi = errors.New("Migrations with " + NoTransactionDirective + " cannot be applied through the Management API.")
+	ErrProgressMismatch    = errors.New("Statements of a partially applied migration have changed.")
+)
+
+// segment is a range of statements sent to the database in the same batch.
+type segment struct {
+	start, end int
+	noTx       bool
+}
+
+func hasNoTransactionDirective(stat string) bool {
+	for _, line := range strings.Split(stat, "\n") {
+		line = strings.TrimSpace(line)
+		if line == NoTransactionDirective {
+			return true
+		}
+		// Directive must appear in the comments preceding the statement
+		if len(line) > 0 && !strings.HasPrefix(line, "--") {
+			break
+		}
+	}
+	return false
+}
+
+func (m *MigrationFile) segments() []segment {
+	var result []segment
+	fileLevel := len(m.Statements) > 0 && hasNoTransactionDirective(m.Statements[0])
+	for i, stat := range m.Statements {
+		if fileLevel || hasNoTransactionDirective(stat) {
+			result = append(result, segment{start: i, end: i + 1, noTx: true})
+		} else if n := len(result); n > 0 && !result[n-1].noTx {
+			result[n-1].end = i + 1
+		} else {
+			result = append(result, segment{start: i, end: i + 1})
+		}
+	}
+	return result
+}
+
+// IsTransactional reports whether all statements can be applied in a single implicit transaction.
+func (m *MigrationFile) IsTransactional() bool {
+	for _, s := range m.segments() {
+		if s.noTx {
+			return false
+		}
+	}
+	return true
+}
+
+// execSegments applies statements marked with NoTransactionDirective in their own batch. The
+// number of applied statements is tracked in a progress table so that a failed migration can
+// be resumed from the failing statement. The version is only inserted after all statements
+// succeed.
+func (m *MigrationFile) execSegments(ctx context.Context, conn *pgx.Conn) error {
+	applied, running := 0, false
+	if len(m.Version) > 0 {
+		var err error
+		if applied, running, err = m.readProgress(ctx, conn); err != nil {
+			return err
+		} else if applied > 0 {
+			fmt.Fprintf(os.Stderr, "Resuming migration %s from statement %d...\n", m.Version, applied)
+		}
+	}
+	for _, s := range m.segments() {
+		if s.end <= applied {
+			continue
+		}
+		// The previous run exited while this statement was running, so it may have succeeded
+		// without its progress being recorded. Rerunning it is only safe if it is idempotent.
+		if s.noTx && running && s.start == applied {
+			fmt.Fprintf(os.Stderr, "WARN: retrying statement %d of %d outside transaction, which must be idempotent:\n%s\n", s.start+1, len(m.Statements), m.Statements[s.start])
+		}
+		if len(m.Version) > 0 && s.noTx {
+			if err := m.execProgress(ctx, conn, s.start, true); err != nil {
+				return err
+			}
+		}
+		start := max(s.start, applied)
+		batch := &pgconn.Batch{}
+		for _, line := range m.Statements[start:s.end] {
+			batch.ExecParams(line, nil, nil, nil, nil)
+		}
+		// Progress of transactional statements is committed atomically
+		if len(m.Version) > 0 && !s.noTx {
+			m.updateProgressSQL(batch, s.end, false)
+		}
+		if result, err := conn.PgConn().ExecBatch(ctx, batch).ReadAll(); err != nil {
+			if len(m.Version) > 0 && s.noTx {
+				// Best effort because failing to clear the flag only adds a warning on resume
+				_ = m.execProgress(ctx, conn, s.start, false)
+			}
+			return m.partialError(m.batchError(err, start+len(result), s.end, UPSERT_MIGRATION_PROGRESS))
+		}
+		if len(m.Version) > 0 && s.noTx {
+			if err := m.execProgress(ctx, conn, s.end, false); err != nil {
+				return err
+			}
+		}
+	}
+	if len(m.Version) == 0 {
+		return nil
+	}
+	// Insert into migration history
+	batch := &pgconn.Batch{}
+	if err := m.insertVersionSQL(conn, batch); err != nil {
+		return err
+	}
+	if len(m.Rollback) > 0 {
+		if err := m.updateRollbackSQL(conn, batch); err != nil {
+			return err
+		}
+	}
+	batch.ExecParams(DELETE_MIGRATION_PROGRESS, [][]byte{[]byte(m.Version)}, []uint32{pgtype.TextOID}, []int16{pgtype.TextFormatCode}, nil)
+	if _, err := conn.PgConn().ExecBatch(ctx, batch).ReadAll(); err != nil {
+		return m.partialError(errors.Errorf("failed to insert migration version: %w", err))
+	}
+	return nil
+}
+
+// readProgress returns the number of applied statements, and whether the next statement was
+// running outside a transaction when the previous run exited.
+func (m *MigrationFile) readProgress(ctx context.Context, conn *pgx.Conn) (int, bool, error) {
+	if _, err := conn.Exec(ctx, CREATE_PROGRESS_TABLE); err != nil {
+		return 0, false, errors.Errorf("failed to create progress table: %w", err)
+	}
+	var applied int
+	var hash string
+	var running bool
+	if err := conn.QueryRow(ctx, SELECT_MIGRATION_PROGRESS, m.Version).Scan(&applied, &hash, &running); errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, errors.Errorf("failed to read migration progress: %w", err)
+	}
+	// Statements after the failing one may be edited before resuming
+	if applied > len(m.Statements) || hash != checksumOf(m.Statements[:applied]) {
+		return 0, false, errors.Errorf("%w\nRevert the applied statements of migration %s manually, then run:\n%s", ErrProgressMismatch, m.Version,
+			strings.Replace(DELETE_MIGRATION_PROGRESS, "$1", "'"+m.Version+"'", 1))
+	}
+	return applied, running, nil
+}
+
+func (m *MigrationFile) updateProgressSQL(batch *pgconn.Batch, applied int, running bool) {
+	batch.ExecParams(
+		UPSERT_MIGRATION_PROGRESS,
+		[][]byte{[]byte(m.Version), []byte(checksumOf(m.Statements[:applied])), []byte(strconv.Itoa(applied)), []byte(strconv.FormatBool(running))},
+		[]uint32{pgtype.TextOID, pgtype.TextOID, pgtype.Int4OID, pgtype.BoolOID},
+		[]int16{pgtype.TextFormatCode, pgtype.TextFormatCode, pgtype.TextFormatCode, pgtype.TextFormatCode},
+		nil,
+	)
+}
+
+func (m *MigrationFile) execProgress(ctx context.Context, conn *pgx.Conn, applied int, running bool) error {
+	batch := &pgconn.Batch{}
+	m.updateProgressSQL(batch, applied, running)
+	if _, err := conn.PgConn().ExecBatch(ctx, batch).ReadAll(); err != nil {
+		return m.partialError(errors.Errorf("failed to update migration progress: %w", err))
+	}
+	return nil
+}
+
+func (m *MigrationFile) partialError(err error) error {
+	if len(m.Version) == 0 {
+		return err
+	}
+	return errors.Errorf("%w\nMigration %s is partially applied. Fix the failing statement and rerun to resume.", err, m.Version)
+}
+
+func checksumOf(statements []string) string {
+	file := MigrationFile{Statements: statements}
+	return file.Checksum()
+}
@@ -0,0 +1,180 @@
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/supabase/cli/pkg/pgtest"
+)
+
+func TestSegments(t *testing.T) {
+	t.Run("splits statements marked with directive", func(t *testing.T) {
+		migration := MigrationFile{Statements: []string{
+			"create table a (id int)",
+			"create table b (id int)",
+			NoTransactionDirective + "\ncreate index concurrently a_id on a (id)",
+			"insert into a values (1)",
+		}}
+		// Run test
+		segments := migration.segments()
+		// Check error
+		assert.Equal(t, []segment{
+			{start: 0, end: 2},
+			{start: 2, end: 3, noTx: true},
+			{start: 3, end: 4},
+		}, segments)
+		assert.False(t, migration.IsTransactional())
+	})
+
+	t.Run("applies file level directive to all statements", func(t *testing.T) {
+		migration := MigrationFile{Statements: []string{
+			NoTransactionDirective + "\n\nvacuum a",
+			"vacuum b",
+		}}
+		// Run test
+		segments := migration.segments()
+		// Check error
+		assert.Equal(t, []segment{
+			{start: 0, end: 1, noTx: true},
+			{start: 1, end: 2, noTx: true},
+		}, segments)
+	})
+
+	t.Run("ignores directive after statement", func(t *testing.T) {
+		migration := MigrationFile{Statements: []string{
+			"select 1 -- supabase:no-transaction",
+			"select '\n" + NoTransactionDirective + "'",
+		}}
+		// Check error
+		assert.True(t, migration.IsTransactional())
+	})
+}
+
+func TestExecSegments(t *testing.T) {
+	migration := MigrationFile{
+		Statements: []string{
+			"create table a (id int)",
+			NoTransactionDirective + "\ncreate index concurrently a_id on a (id)",
+		},
+		Version: "0",
+	}
+
+	t.Run("applies statements outside transaction", func(t *testing.T) {
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(CREATE_PROGRESS_TABLE).
+			Reply("CREATE TABLE").
+			Query(SELECT_MIGRATION_PROGRESS, "0").
+			Reply("SELECT 0").
+			Query(migration.Statements[0]).
+			Reply("CREATE TABLE").
+			Query(UPSERT_MIGRATION_PROGRESS, "0", checksumOf(migration.Statements[:1]), "1", "false").
+			Reply("INSERT 0 1").
+			Query(UPSERT_MIGRATION_PROGRESS, "0", checksumOf(migration.Statements[:1]), "1", "true").
+			Reply("INSERT 0 1").
+			Query(migration.Statements[1]).
+			Reply("CREATE INDEX").
+			Query(UPSERT_MIGRATION_PROGRESS, "0", migration.Checksum(), "2", "false").
+			Reply("INSERT 0 1").
+			Query(INSERT_MIGRATION_VERSION, "0", "", migration.Statements, migration.Checksum()).
+			Reply("INSERT 0 1").
+			Query(DELETE_MIGRATION_PROGRESS, "0").
+			Reply("DELETE 1")
+		// Run test
+		err := migration.ExecBatch(context.Background(), conn.MockClient(t))
+		// Check error
+		assert.NoError(t, err)
+	})
+
+	t.Run("resumes partially applied migration", func(t *testing.T) {
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(CREATE_PROGRESS_TABLE).
+			Reply("CREATE TABLE").
+			Query(SELECT_MIGRATION_PROGRESS, "0").
+			Reply("SELECT 1", []any{1, checksumOf(migration.Statements[:1]), false}).
+			Query(UPSERT_MIGRATION_PROGRESS, "0", checksumOf(migration.Statements[:1]), "1", "true").
+			Reply("INSERT 0 1").
+			Query(migration.Statements[1]).
+			Reply("CREATE INDEX").
+			Query(UPSERT_MIGRATION_PROGRESS, "0", migration.Checksum(), "2", "false").
+			Reply("INSERT 0 1").
+			Query(INSERT_MIGRATION_VERSION, "0", "", migration.Statements, migration.Checksum()).
+			Reply("INSERT 0 1").
+			Query(DELETE_MIGRATION_PROGRESS, "0").
+			Reply("DELETE 1")
+		// Run test
+		err := migration.ExecBatch(context.Background(), conn.MockClient(t))
+		// Check error
+		assert.NoError(t, err)
+	})
+
+	t.Run("retries statement interrupted outside transaction", func(t *testing.T) {
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(CREATE_PROGRESS_TABLE).
+			Reply("CREATE TABLE").
+			Query(SELECT_MIGRATION_PROGRESS, "0").
+			Reply("SELECT 1", []any{1, checksumOf(migration.Statements[:1]), true}).
+			Query(UPSERT_MIGRATION_PROGRESS, "0", checksumOf(migration.Statements[:1]), "1", "true").
+			Reply("INSERT 0 1").
+			Query(migration.Statements[1]).
+			Reply("CREATE INDEX").
+			Query(UPSERT_MIGRATION_PROGRESS, "0", migration.Checksum(), "2", "false").
+			Reply("INSERT 0 1").
+			Query(INSERT_MIGRATION_VERSION, "0", "", migration.Statements, migration.Checksum()).
+			Reply("INSERT 0 1").
+			Query(DELETE_MIGRATION_PROGRESS, "0").
+			Reply("DELETE 1")
+		// Run test
+		err := migration.ExecBatch(context.Background(), conn.MockClient(t))
+		// Check error
+		assert.NoError(t, err)
+	})
+
+	t.Run("throws error on changed statements", func(t *testing.T) {
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(CREATE_PROGRESS_TABLE).
+			Reply("CREATE TABLE").
+			Query(SELECT_MIGRATION_PROGRESS, "0").
+			Reply("SELECT 1", []any{1, checksumOf([]string{"create table b (id int)"}), false})
+		// Run test
+		err := migration.ExecBatch(context.Background(), conn.MockClient(t))
+		// Check error
+		assert.ErrorIs(t, err, ErrProgressMismatch)
+		assert.ErrorContains(t, err, "DELETE FROM supabase_migrations.schema_migration_progress WHERE version = '0'")
+	})
+
+	t.Run("throws error on partial failure", func(t *testing.T) {
+		// Setup mock postgres
+		conn := pgtest.NewConn()
+		defer conn.Close(t)
+		conn.Query(CREATE_PROGRESS_TABLE).
+			Reply("CREATE TABLE").
+			Query(SELECT_MIGRATION_PROGRESS, "0").
+			Reply("SELECT 0").
+			Query(migration.Statements[0]).
+			Reply("CREATE TABLE").
+			Query(UPSERT_MIGRATION_PROGRESS, "0", checksumOf(migration.Statements[:1]), "1", "false").
+			Reply("INSERT 0 1").
+			Query(UPSERT_MIGRATION_PROGRESS, "0", checksumOf(migration.Statements[:1]), "1", "true").
+			Reply("INSERT 0 1").
+			Query(migration.Statements[1]).
+			ReplyError(pgerrcode.UniqueViolation, `could not create unique index "a_id"`).
+			Query(UPSERT_MIGRATION_PROGRESS, "0", checksumOf(migration.Statements[:1]), "1", "false").
+			Reply("INSERT 0 1")
+		// Run test
+		err := migration.ExecBatch(context.Background(), conn.MockClient(t))
+		// Check error
+		assert.ErrorContains(t, err, `ERROR: could not create unique index "a_id" (SQLSTATE 23505)`)
+		assert.ErrorContains(t, err, "At statement: 1")
+		assert.ErrorContains(t, err, "Migration 0 is partially applied.")
+	})
+}
@@ -242,6 +242,19 @@ func (r *MockConn) ReplyError(code, message string) *MockConn {
 	return r
 }
 
+// Simulates an error reply from the server when preparing a statement, ie. when the
+// statement references an undefined table or column.
+func (r *MockConn) ReplyPrepareError(code, message string) *MockConn {
+	q := r.lastQuery()
+	q.prepareErr = &pgproto3.ErrorResponse{
+		Severity:            "ERROR",
+		SeverityUnlocalized: "ERROR",
+		Code:                code,
+		Message:             message,
+	}
+	return r
+}
+
 func (r *MockConn) Close(t *testing.T) {
 	if r.client != nil {
 		if err := r.client.Close(context.Background()); err != nil {
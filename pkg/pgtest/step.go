@@ -16,6 +16,8 @@ type extendedQueryStep struct {
 	params [][]byte
 	oids   []uint32
 	reply  pgmock.Script
+	// Error returned by the server when preparing a named statement
+	prepareErr *pgproto3.ErrorResponse
 }
 
 func (e *extendedQueryStep) Step(backend *pgproto3.Backend) error {
@@ -31,7 +33,15 @@ func (e *extendedQueryStep) Step(backend *pgproto3.Backend) error {
 			return errors.Errorf("expected => %#v\nactual => %#v", want, m)
 		}
 		// Anonymous ps falls through
-		if m.Name != "" {
+		if m.Name != "" && e.prepareErr != nil {
+			script := pgmock.Script{Steps: []pgmock.Step{
+				pgmock.ExpectMessage(&pgproto3.Describe{ObjectType: 'S', Name: m.Name}),
+				pgmock.ExpectMessage(&pgproto3.Sync{}),
+				pgmock.SendMessage(e.prepareErr),
+				pgmock.SendMessage(&pgproto3.ReadyForQuery{TxStatus: 'I'}),
+			}}
+			return script.Run(backend)
+		} else if m.Name != "" {
 			script := pgmock.Script{Steps: []pgmock.Step{
 				pgmock.ExpectMessage(&pgproto3.Describe{ObjectType: 'S', Name: m.Name}),
 				pgmock.ExpectMessage(&pgproto3.Sync{}),